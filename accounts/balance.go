@@ -0,0 +1,106 @@
+// Package accounts implements the commands that inspect, fund and configure accounts.
+package accounts
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/nikhilsaraf/stellar-go/asset"
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/stellar/go/clients/horizon"
+)
+
+// BalanceCmd prints the balances of an account
+var BalanceCmd = &cli.Command{
+	Name:    "balance",
+	Summary: "print the balances of an account",
+	Usage:   "-a <address>",
+	Run:     runBalance,
+}
+
+func runBalance(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	addressPtr := fs.String("a", "", "string representing the address to be used")
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	if *addressPtr == "" {
+		return cli.UsageErrorf("the -a flag is required")
+	}
+
+	p, e := ctx.Profile()
+	if e != nil {
+		return e
+	}
+	fmt.Fprintln(ctx.Info(), "network:", p)
+	fmt.Fprintln(ctx.Info(), "address:", *addressPtr)
+
+	account, e := p.Client().LoadAccount(*addressPtr)
+	if e != nil {
+		return fmt.Errorf("could not load account %s: %s", *addressPtr, e)
+	}
+	return ctx.Emit(NewAccountBalances(account), func(w io.Writer) {
+		printBalances(w, *addressPtr, account, "")
+	})
+}
+
+// AccountBalances is the JSON output of the balance command
+type AccountBalances struct {
+	Address  string    `json:"address"`
+	Sequence string    `json:"sequence"`
+	Balances []Balance `json:"balances"`
+}
+
+// Balance is a single balance of an account, Asset is "native" for lumens and "code:issuer" otherwise
+type Balance struct {
+	Asset   string `json:"asset"`
+	Balance string `json:"balance"`
+	Limit   string `json:"limit,omitempty"`
+}
+
+// NewAccountBalances converts the balances of an account loaded from Horizon
+func NewAccountBalances(account horizon.Account) *AccountBalances {
+	ab := &AccountBalances{
+		Address:  account.AccountID,
+		Sequence: account.Sequence,
+		Balances: []Balance{},
+	}
+	for _, balance := range account.Balances {
+		b := Balance{Asset: asset.HorizonString(balance.Asset), Balance: balance.Balance}
+		if balance.Asset.Type != "native" {
+			b.Limit = balance.Limit
+		}
+		ab.Balances = append(ab.Balances, b)
+	}
+	return ab
+}
+
+// Load fetches the account from Horizon and prints its balances as progress output, name is used to label the account
+func Load(ctx *cli.Context, client *horizon.Client, address string, name string) (horizon.Account, error) {
+	account, e := client.LoadAccount(address)
+	if e != nil {
+		return account, fmt.Errorf("could not load account %s: %s", address, e)
+	}
+	printBalances(ctx.Info(), address, account, name)
+	return account, nil
+}
+
+func printBalances(w io.Writer, address string, account horizon.Account, name string) {
+	if name == "" {
+		fmt.Fprintln(w, "Balances for account:", address)
+	} else {
+		fmt.Fprintln(w, "Balances for account ("+name+"):")
+	}
+	for _, balance := range account.Balances {
+		fmt.Fprintln(w, "   ", FormatBalance(balance))
+	}
+}
+
+// FormatBalance formats a single balance line, e.g. "100.0000000 USD:GABC... (limit 1000.0000000)"
+func FormatBalance(balance horizon.Balance) string {
+	if balance.Asset.Type == "native" {
+		return balance.Balance + " XLM"
+	}
+	return fmt.Sprintf("%s %s:%s (limit %s)", balance.Balance, balance.Asset.Code, balance.Asset.Issuer, balance.Limit)
+}
@@ -0,0 +1,77 @@
+package accounts
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+)
+
+// FundCmd creates and funds an account using the network's friendbot
+var FundCmd = &cli.Command{
+	Name:    "fund",
+	Summary: "create and fund an account using the network's friendbot",
+	Usage:   "[-a <address>]",
+	Run:     runFund,
+}
+
+func runFund(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	addressPtr := fs.String("a", "", "(optional) address of the new account, read from stdin if unspecified")
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+
+	p, e := ctx.Profile()
+	if e != nil {
+		return e
+	}
+	if p.FriendbotURL == "" {
+		return fmt.Errorf("network profile '%s' does not have a friendbot_url", p.Name)
+	}
+	fmt.Fprintln(ctx.Info(), "Using friendbot:", p.FriendbotURL)
+
+	address := *addressPtr
+	if address == "" {
+		address, e = ctx.ReadLine("Enter address for new account:\n")
+		if e != nil {
+			return e
+		}
+	}
+	fmt.Fprintln(ctx.Info(), "Address entered:", address)
+
+	resp, e := http.Get(p.FriendbotURL + "?addr=" + url.QueryEscape(address))
+	if e != nil {
+		return e
+	}
+	defer resp.Body.Close()
+
+	body, e := ioutil.ReadAll(resp.Body)
+	if e != nil {
+		return e
+	}
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintln(ctx.Stderr, string(body))
+		return fmt.Errorf("friendbot returned status %d", resp.StatusCode)
+	}
+
+	result := fundResult{Address: address, Response: json.RawMessage(body)}
+	if !json.Valid(body) {
+		quoted, _ := json.Marshal(string(body))
+		result.Response = json.RawMessage(quoted)
+	}
+	return ctx.Emit(result, func(w io.Writer) {
+		fmt.Fprintln(w, string(body))
+	})
+}
+
+// fundResult is the JSON output of the fund command, Response is the friendbot's reply
+type fundResult struct {
+	Address  string          `json:"address"`
+	Response json.RawMessage `json:"friendbot_response"`
+}
@@ -0,0 +1,84 @@
+package accounts
+
+import (
+	"fmt"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/federation"
+	"github.com/nikhilsaraf/stellar-go/secret"
+	"github.com/nikhilsaraf/stellar-go/submit"
+	b "github.com/stellar/go/build"
+)
+
+// SetInflationCmd sets the inflation destination of an account
+var SetInflationCmd = &cli.Command{
+	Name:    "set-inflation",
+	Summary: "set the inflation destination of an account",
+	Usage:   "[-secret <source>] -a <address|name*domain> [-dry-run | -unsigned -sequence <n>] [-out <file>]",
+	Run:     runSetInflation,
+}
+
+func runSetInflation(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	addressPtr := fs.String("a", "", "string representing the inflation destination address to be used, or a federation address such as bob*example.com")
+	secretPtr := secret.Flag(fs, "secret", "secret key of the account to update")
+	opts := submit.Flags(fs)
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	e = opts.Validate()
+	if e != nil {
+		return e
+	}
+
+	p, e := ctx.Profile()
+	if e != nil {
+		return e
+	}
+	if opts.Offline() && federation.IsAddress(*addressPtr) {
+		return cli.UsageErrorf("federation addresses cannot be resolved without network access, use the account ID with -unsigned")
+	}
+	inflationAddress := *addressPtr
+	if inflationAddress != "" {
+		dest, e := federation.Resolve(ctx.HTTPClient(), inflationAddress)
+		if e != nil {
+			return e
+		}
+		if dest.MemoType != "" && dest.MemoType != "none" {
+			fmt.Fprintf(ctx.Stderr, "note: the memo of %s does not apply to an inflation destination\n", dest.StellarAddress)
+		}
+		inflationAddress = dest.AccountID
+	}
+	horizonClient := p.Client()
+	fmt.Fprintln(ctx.Info(), "inflation destination:", inflationAddress)
+	fmt.Fprintln(ctx.Info(), "network:", p)
+
+	if inflationAddress != "" && !opts.Offline() {
+		_, e = Load(ctx, horizonClient, inflationAddress, "inflation address")
+		if e != nil {
+			return e
+		}
+	}
+
+	sourceAddress, sourceKP, e := opts.Signer(*secretPtr, ctx)
+	if e != nil {
+		return e
+	}
+	fmt.Fprintln(ctx.Info(), "source account", sourceAddress+", setting inflation destination now.")
+
+	txn, e := opts.Build(p, horizonClient, sourceAddress,
+		b.SetOptions(
+			b.InflationDest(inflationAddress),
+		),
+	)
+	if e != nil {
+		return e
+	}
+	env, e := txn.Sign()
+	if e != nil {
+		return e
+	}
+	_, e = opts.Finish(ctx, &env, sourceKP, horizonClient)
+	return e
+}
@@ -0,0 +1,116 @@
+package accounts
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/federation"
+	"github.com/nikhilsaraf/stellar-go/sequence"
+	"github.com/nikhilsaraf/stellar-go/timebounds"
+	b "github.com/stellar/go/build"
+)
+
+const migrateInflationDest = "GCCD6AJOYZCUAQLX32ZJF2MKFFAUJ53PVCFQI3RHWKL3V47QYE2BNAUT"
+const startingBalanceXlm = "100.0"
+
+// MigrateCmd generates the unsigned XDR that migrates the funds of one account into a new account
+var MigrateCmd = &cli.Command{
+	Name:    "migrate",
+	Summary: "generate the unsigned XDR that migrates an account into a new account",
+	Usage:   "-from <address> -dest <address|name*domain> -seq_offset <n> [-valid-for <duration>] [-min-time <time>] [-max-time <time>]",
+	Run:     runMigrate,
+}
+
+func runMigrate(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	fromAccountPtr := fs.String("from", "", "stellar account that needs to be migrated")
+	destAccountPtr := fs.String("dest", "", "destination stellar account where we want to migrate to, or a federation address such as bob*example.com")
+	seqOffsetPtr := fs.Int64("seq_offset", -1, "sequence number offset (0 for the next valid seq number, only +ve numbers)")
+	bounds := timebounds.Flags(fs)
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	if *fromAccountPtr == "" || *destAccountPtr == "" || *seqOffsetPtr < 0 {
+		return cli.UsageErrorf("the -from, -dest and -seq_offset flags are required")
+	}
+	e = bounds.Validate()
+	if e != nil {
+		return cli.UsageErrorf("%s", e)
+	}
+	extra, e := bounds.Mutators(time.Now())
+	if e != nil {
+		return e
+	}
+
+	p, e := ctx.Profile()
+	if e != nil {
+		return e
+	}
+	fmt.Fprintf(ctx.Info(), "network: %s\n", p)
+
+	dest, e := federation.Resolve(ctx.HTTPClient(), *destAccountPtr)
+	if e != nil {
+		return e
+	}
+	memo, e := dest.MemoMutator("")
+	if e != nil {
+		return e
+	}
+	if memo != nil {
+		extra = append(extra, memo)
+	}
+	fmt.Fprintf(ctx.Info(), "destination: %s\n", dest)
+
+	muts := []b.TransactionMutator{
+		b.SourceAccount{AddressOrSeed: *fromAccountPtr},
+		b.AutoSequence{
+			SequenceProvider: sequence.OffsetProvider{
+				Inner:  p.Client(),
+				Offset: *seqOffsetPtr,
+				Log:    ctx.Stderr,
+			},
+		},
+		p.Network(),
+		b.BaseFee{Amount: p.BaseFee},
+		b.CreateAccount(
+			b.Destination{AddressOrSeed: dest.AccountID},
+			b.NativeAmount{Amount: startingBalanceXlm},
+		),
+		b.AccountMerge(
+			b.Destination{AddressOrSeed: dest.AccountID},
+		),
+		b.SetOptions(
+			b.SourceAccount{AddressOrSeed: dest.AccountID},
+			b.InflationDest(migrateInflationDest),
+		),
+	}
+	txn, e := b.Transaction(append(muts, extra...)...)
+	if e != nil {
+		return e
+	}
+
+	// sign with empty signature so it gets converted to a transaction envelope
+	txEnv, e := txn.Sign()
+	if e != nil {
+		return fmt.Errorf("failed to sign: %s", e)
+	}
+
+	// convert to base64
+	txEnvBase64, e := txEnv.Base64()
+	if e != nil {
+		return fmt.Errorf("failed to convert to base64: %s", e)
+	}
+
+	return ctx.Emit(migrateResult{Envelope: txEnvBase64}, func(w io.Writer) {
+		fmt.Fprintf(w, "\nxdr:\n")
+		fmt.Fprintf(w, "%s\n", txEnvBase64)
+	})
+}
+
+// migrateResult is the JSON output of the migrate command
+type migrateResult struct {
+	Envelope string `json:"envelope"`
+}
@@ -0,0 +1,124 @@
+package accounts_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nikhilsaraf/stellar-go/accounts"
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/envelope"
+	"github.com/nikhilsaraf/stellar-go/federation"
+	"github.com/nikhilsaraf/stellar-go/horizontest"
+	"github.com/stellar/go/keypair"
+)
+
+var root = &cli.Command{Name: "stellar", Subcommands: []*cli.Command{accounts.MigrateCmd}}
+
+// migrate runs the migrate command with JSON output and decodes the envelope it prints
+func migrate(t *testing.T, s *horizontest.Server, args ...string) *envelope.Description {
+	r := s.Run(root, "", append([]string{"--output", "json", "migrate"}, args...)...)
+	if r.Code != 0 {
+		t.Fatalf("exit %d: %s", r.Code, r.Stderr)
+	}
+	var result struct {
+		Envelope string `json:"envelope"`
+	}
+	e := json.Unmarshal([]byte(r.Stdout), &result)
+	if e != nil {
+		t.Fatalf("%s: %s", e, r.Stdout)
+	}
+	d, e := horizontest.Describe(result.Envelope)
+	if e != nil {
+		t.Fatal(e)
+	}
+	return d
+}
+
+func TestMigrate(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	from, _ := keypair.Random()
+	dest, _ := keypair.Random()
+	s.CreateAccount(from.Address(), "500")
+
+	d := migrate(t, s, "-from", from.Address(), "-dest", dest.Address(), "-seq_offset", "2")
+	if d.Source != from.Address() {
+		t.Errorf("source %s, want %s", d.Source, from.Address())
+	}
+	if want := s.Sequence(from.Address()) + 3; d.Sequence != want {
+		t.Errorf("sequence %d, want %d", d.Sequence, want)
+	}
+	if len(d.Signatures) != 0 {
+		t.Errorf("got %d signatures, the envelope must be unsigned", len(d.Signatures))
+	}
+
+	want := []struct {
+		typ    string
+		source string
+		fields []string
+	}{
+		{"create_account", "", []string{dest.Address(), "100.0000000"}},
+		{"account_merge", "", []string{dest.Address()}},
+		{"set_options", dest.Address(), []string{"GCCD6AJOYZCUAQLX32ZJF2MKFFAUJ53PVCFQI3RHWKL3V47QYE2BNAUT"}},
+	}
+	if len(d.Operations) != len(want) {
+		t.Fatalf("operations %+v, want %d", d.Operations, len(want))
+	}
+	for i, op := range d.Operations {
+		if op.Type != want[i].typ || op.Source != want[i].source || len(op.Fields) != len(want[i].fields) {
+			t.Errorf("operation %d is %+v, want %+v", i, op, want[i])
+			continue
+		}
+		for j, f := range op.Fields {
+			if f.Value != want[i].fields[j] {
+				t.Errorf("operation %d: %s is %s, want %s", i, f.Name, f.Value, want[i].fields[j])
+			}
+		}
+	}
+	// the envelope is only printed, the account is not migrated
+	if txs := s.Transactions(); len(txs) != 0 {
+		t.Errorf("got %d transactions, want none", len(txs))
+	}
+}
+
+func TestMigrateFederation(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	from, _ := keypair.Random()
+	dest, _ := keypair.Random()
+	s.CreateAccount(from.Address(), "500")
+	s.AddFederation(federation.Record{StellarAddress: "bob*example.com", AccountID: dest.Address(), MemoType: "text", Memo: "savings"})
+
+	d := migrate(t, s, "-from", from.Address(), "-dest", "bob*example.com", "-seq_offset", "0")
+	if d.Operations[0].Fields[0].Value != dest.Address() {
+		t.Errorf("migrating to %s, want %s", d.Operations[0].Fields[0].Value, dest.Address())
+	}
+	if d.Memo.Type != "text" || d.Memo.Value != "savings" {
+		t.Errorf("memo %+v, want the text savings of the federation record", d.Memo)
+	}
+}
+
+func TestMigrateErrors(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	from, _ := keypair.Random()
+	dest, _ := keypair.Random()
+	s.CreateAccount(from.Address(), "500")
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"missing offset", []string{"-from", from.Address(), "-dest", dest.Address()}, 2},
+		{"missing destination", []string{"-from", from.Address(), "-seq_offset", "0"}, 2},
+		{"unknown federation address", []string{"-from", from.Address(), "-dest", "nobody*example.com", "-seq_offset", "0"}, 1},
+		{"missing account", []string{"-from", dest.Address(), "-dest", from.Address(), "-seq_offset", "0"}, 1},
+	}
+	for _, test := range tests {
+		r := s.Run(root, "", append([]string{"migrate"}, test.args...)...)
+		if r.Code != test.code {
+			t.Errorf("%s: exit %d, want %d: %s", test.name, r.Code, test.code, r.Stderr)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Package asset parses and validates assets and converts them into the representations used by the transaction
+// builder and by Horizon. Lumens are written as "native" or "XLM" and have no issuer, issued assets as CODE:ISSUER or
+// CODE-ISSUER.
+package asset
+
+import (
+	"fmt"
+	"strings"
+
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// NativeCode is the code used for lumens
+const NativeCode = "native"
+
+// maxCodeLength is the length of the longest code of a credit_alphanum12 asset
+const maxCodeLength = 12
+
+// Asset is a validated asset, the Issuer of lumens is empty
+type Asset struct {
+	Code   string
+	Issuer string
+}
+
+// Native is the asset for lumens
+var Native = Asset{Code: NativeCode}
+
+// Parse parses "native", "XLM", "CODE:ISSUER" or "CODE-ISSUER"
+func Parse(s string) (Asset, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Asset{}, fmt.Errorf("the asset is empty, expected native, XLM, CODE:ISSUER or CODE-ISSUER")
+	}
+	if strings.EqualFold(s, NativeCode) || strings.EqualFold(s, "XLM") {
+		return Native, nil
+	}
+	// codes are alphanumeric so the first separator ends the code
+	i := strings.IndexAny(s, ":-")
+	if i < 0 {
+		return Asset{}, fmt.Errorf("the asset '%s' has no issuer, expected native, XLM, CODE:ISSUER or CODE-ISSUER", s)
+	}
+	return newCredit(s[:i], s[i+1:])
+}
+
+// New validates an asset given as separate code and issuer flags, the code may also be any form accepted by Parse
+// when the issuer is empty
+func New(code string, issuer string) (Asset, error) {
+	if issuer == "" {
+		return Parse(code)
+	}
+	if strings.EqualFold(code, NativeCode) {
+		return Asset{}, fmt.Errorf("lumens do not have an issuer, leave out the issuer of the native asset")
+	}
+	return newCredit(code, issuer)
+}
+
+func newCredit(code string, issuer string) (Asset, error) {
+	e := checkCode(code)
+	if e != nil {
+		return Asset{}, e
+	}
+	e = checkIssuer(issuer)
+	if e != nil {
+		return Asset{}, e
+	}
+	return Asset{Code: code, Issuer: issuer}, nil
+}
+
+// checkCode checks that the code has 1 to 12 letters and digits
+func checkCode(code string) error {
+	if code == "" {
+		return fmt.Errorf("the asset code is empty")
+	}
+	if len(code) > maxCodeLength {
+		return fmt.Errorf("the asset code '%s' is longer than %d characters", code, maxCodeLength)
+	}
+	for _, c := range code {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return fmt.Errorf("the asset code '%s' contains '%c', only the letters a-z, A-Z and the digits 0-9 are allowed", code, c)
+		}
+	}
+	return nil
+}
+
+// checkIssuer checks that the issuer is an account address with a valid checksum
+func checkIssuer(issuer string) error {
+	if issuer == "" {
+		return fmt.Errorf("the asset issuer is empty")
+	}
+	if strings.HasPrefix(issuer, "S") {
+		return fmt.Errorf("the asset issuer must be an address starting with G, not a secret seed")
+	}
+	_, e := strkey.Decode(strkey.VersionByteAccountID, issuer)
+	if e != nil {
+		return fmt.Errorf("the asset issuer '%s' is not a valid address, expected 56 characters starting with G with a correct checksum", issuer)
+	}
+	return nil
+}
+
+// IsNative reports whether the asset is lumens
+func (a Asset) IsNative() bool {
+	return a.Code == NativeCode && a.Issuer == ""
+}
+
+// Type returns the asset type as named by Horizon, the type of an issued asset depends on the length of its code
+func (a Asset) Type() string {
+	if a.IsNative() {
+		return "native"
+	} else if len(a.Code) <= 4 {
+		return "credit_alphanum4"
+	}
+	return "credit_alphanum12"
+}
+
+// String formats the asset as "native" or "code:issuer"
+func (a Asset) String() string {
+	if a.IsNative() {
+		return NativeCode
+	}
+	return a.Code + ":" + a.Issuer
+}
+
+// Build returns the transaction builder's representation of the asset
+func (a Asset) Build() b.Asset {
+	if a.IsNative() {
+		return b.NativeAsset()
+	}
+	return b.CreditAsset(a.Code, a.Issuer)
+}
+
+// Amount returns the payment mutator that pays the amount of the asset
+func (a Asset) Amount(amount string) b.PaymentMutator {
+	if a.IsNative() {
+		return b.NativeAmount{Amount: amount}
+	}
+	return b.CreditAmount{Code: a.Code, Issuer: a.Issuer, Amount: amount}
+}
+
+// Horizon returns Horizon's representation of the asset
+func (a Asset) Horizon() horizon.Asset {
+	if a.IsNative() {
+		return horizon.Asset{Type: a.Type()}
+	}
+	return horizon.Asset{Type: a.Type(), Code: a.Code, Issuer: a.Issuer}
+}
+
+// Has reports whether the account has a balance, i.e. a trust line, for the asset. Accounts always hold lumens.
+func Has(account *horizon.Account, a Asset) bool {
+	if a.IsNative() {
+		return true
+	}
+	for _, balance := range account.Balances {
+		if balance.Asset.Code == a.Code && balance.Asset.Issuer == a.Issuer {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats an XDR asset as "native" or "code:issuer"
+func String(a xdr.Asset) string {
+	var typ, code, issuer string
+	e := a.Extract(&typ, &code, &issuer)
+	if e != nil {
+		return "invalid asset"
+	}
+	if typ == "native" {
+		return NativeCode
+	}
+	return code + ":" + issuer
+}
+
+// HorizonString formats Horizon's representation of an asset as "native" or "code:issuer"
+func HorizonString(a horizon.Asset) string {
+	if a.Type == "native" {
+		return NativeCode
+	}
+	return a.Code + ":" + a.Issuer
+}
@@ -0,0 +1,64 @@
+package asset
+
+import (
+	"testing"
+)
+
+const issuer = "GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Asset
+		typ     string
+		invalid bool
+	}{
+		{in: "native", want: Native, typ: "native"},
+		{in: "XLM", want: Native, typ: "native"},
+		{in: " xlm ", want: Native, typ: "native"},
+		{in: "USD:" + issuer, want: Asset{Code: "USD", Issuer: issuer}, typ: "credit_alphanum4"},
+		{in: "USD-" + issuer, want: Asset{Code: "USD", Issuer: issuer}, typ: "credit_alphanum4"},
+		{in: "ABCD:" + issuer, want: Asset{Code: "ABCD", Issuer: issuer}, typ: "credit_alphanum4"},
+		{in: "ABCDE:" + issuer, want: Asset{Code: "ABCDE", Issuer: issuer}, typ: "credit_alphanum12"},
+		{in: "ABCDEFGHIJKL:" + issuer, want: Asset{Code: "ABCDEFGHIJKL", Issuer: issuer}, typ: "credit_alphanum12"},
+		{in: "", invalid: true},
+		{in: "USD", invalid: true},
+		{in: ":" + issuer, invalid: true},
+		{in: "USD:", invalid: true},
+		{in: "ABCDEFGHIJKLM:" + issuer, invalid: true},
+		{in: "US$:" + issuer, invalid: true},
+		{in: "USD:GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOA", invalid: true},
+		{in: "USD:SBPOVRVKTTV7W3IOX2FJPSMPCJ5L2WU2YKTP3HCLYPXNI5MDIGREVNYC", invalid: true},
+	}
+	for _, test := range tests {
+		got, e := Parse(test.in)
+		if test.invalid {
+			if e == nil {
+				t.Errorf("%q: expected an error, got %s", test.in, got)
+			}
+			continue
+		}
+		if e != nil {
+			t.Errorf("%q: %s", test.in, e)
+			continue
+		}
+		if got != test.want || got.Type() != test.typ {
+			t.Errorf("%q: got %s of type %s, want %s of type %s", test.in, got, got.Type(), test.want, test.typ)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	a, e := New("USD", issuer)
+	if e != nil || a != (Asset{Code: "USD", Issuer: issuer}) {
+		t.Errorf("got %s, %v", a, e)
+	}
+	a, e = New("USD:"+issuer, "")
+	if e != nil || a != (Asset{Code: "USD", Issuer: issuer}) {
+		t.Errorf("got %s, %v", a, e)
+	}
+	_, e = New("native", issuer)
+	if e == nil {
+		t.Error("expected an error for lumens with an issuer")
+	}
+}
@@ -0,0 +1,100 @@
+// Package assets implements the commands that deal with issued assets.
+package assets
+
+import (
+	"fmt"
+
+	"github.com/nikhilsaraf/stellar-go/asset"
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/secret"
+	"github.com/nikhilsaraf/stellar-go/stroops"
+	"github.com/nikhilsaraf/stellar-go/submit"
+	b "github.com/stellar/go/build"
+)
+
+// TrustCmd creates a trust line from the receiver's account to an asset so it can hold the asset
+var TrustCmd = &cli.Command{
+	Name:    "trust",
+	Summary: "create a trust line to an asset",
+	Usage:   "[-secret <source>] -code <code> [-issuer <address>] [-limit <n>] [-dry-run | -unsigned -sequence <n>] [-out <file>]",
+	Run:     runTrust,
+}
+
+func runTrust(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	codePtr := fs.String("code", "", "the code for the asset, or the whole asset as CODE:ISSUER")
+	issuerAddressPtr := fs.String("issuer", "", "the issuer's address")
+	secretPtr := secret.Flag(fs, "secret", "receiver's secret key, the account that will trust the asset")
+	limitPtr := stroops.Flag(fs, "limit", "(optional) limit for trust with up to 7 decimal places, 0 for max limit")
+	opts := submit.Flags(fs)
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	if *codePtr == "" {
+		return cli.UsageErrorf("the -code flag is required")
+	}
+	trusted, e := asset.New(*codePtr, *issuerAddressPtr)
+	if e != nil {
+		return cli.UsageErrorf("invalid asset: %s", e)
+	}
+	if trusted.IsNative() {
+		return cli.UsageErrorf("accounts always hold lumens, a trust line is only needed for issued assets")
+	}
+	e = opts.Validate()
+	if e != nil {
+		return e
+	}
+
+	p, e := ctx.Profile()
+	if e != nil {
+		return e
+	}
+
+	receiverAddress, receiverKP, e := opts.Signer(*secretPtr, ctx)
+	if e != nil {
+		return e
+	}
+	code := trusted.Code
+	issuerAddress := trusted.Issuer
+	limit := *limitPtr
+	fmt.Fprintln(ctx.Info(), "code:", code)
+	fmt.Fprintln(ctx.Info(), "issuerAddress:", issuerAddress)
+	fmt.Fprintln(ctx.Info(), "receiverAddress:", receiverAddress)
+	fmt.Fprintln(ctx.Info(), "network:", p)
+	fmt.Fprintln(ctx.Info(), "limit:", limit)
+
+	client := p.Client()
+	trust := b.Trust(code, issuerAddress)
+	if limit > 0 {
+		trustAmount := limit.String()
+		fmt.Fprintln(ctx.Info(), "setting trust amount:", trustAmount)
+		trust = b.Trust(code, issuerAddress, b.Limit(trustAmount))
+	}
+
+	// validate accounts
+	if !opts.Offline() {
+		_, e = client.LoadAccount(issuerAddress)
+		if e != nil {
+			return fmt.Errorf("could not load issuer account: %s", e)
+		}
+		_, e = client.LoadAccount(receiverAddress)
+		if e != nil {
+			return fmt.Errorf("could not load receiver account: %s", e)
+		}
+	}
+
+	txn, e := opts.Build(p, client, receiverAddress,
+		trust,
+	)
+	if e != nil {
+		return e
+	}
+
+	env, e := txn.Sign()
+	if e != nil {
+		return e
+	}
+	_, e = opts.Finish(ctx, &env, receiverKP, client)
+	return e
+}
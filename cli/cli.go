@@ -0,0 +1,268 @@
+// Package cli is the small command framework shared by all subcommands of the stellar binary.
+//
+// A command tree is built out of Command values. Groups only have Subcommands, leaves have a Run func that defines its
+// flags on the FlagSet returned by Context.FlagSet, parses its args and returns an error instead of exiting. Main takes
+// care of global flags, help text and mapping errors to process exit codes.
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/nikhilsaraf/stellar-go/profile"
+	"github.com/nikhilsaraf/stellar-go/secret"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// process exit codes shared by all commands
+const (
+	ExitOK    = 0
+	ExitError = 1
+	ExitUsage = 2
+)
+
+// ExitCoder is implemented by errors that want the process to exit with a specific code
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// Command is a node in the command tree
+type Command struct {
+	// Name is the word used to invoke the command
+	Name string
+	// Summary is the one-line description shown in help listings
+	Summary string
+	// Usage describes the arguments after the flags, if any
+	Usage string
+	// Run executes a leaf command with the arguments that follow its name
+	Run func(ctx *Context, args []string) error
+	// Subcommands are the children of a group command
+	Subcommands []*Command
+}
+
+// Context carries the global options and I/O streams to a running command
+type Context struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// Network is the name of the network profile selected with the global --network flag
+	Network string
+	// Output is the format selected with the global --output flag, OutputText or OutputJSON
+	Output string
+	// HTTP is the client for requests to servers other than Horizon, such as federation servers, nil means
+	// http.DefaultClient
+	HTTP *http.Client
+
+	path    []string
+	command *Command
+	reader  *bufio.Reader
+}
+
+// usageError is returned for invalid invocations so Main can print help and exit with ExitUsage
+type usageError struct {
+	msg string
+	// shown is set when the flag package has already printed the error along with the usage
+	shown bool
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func (e *usageError) ExitCode() int {
+	return ExitUsage
+}
+
+// UsageErrorf returns an error that makes Main print the command's usage and exit with ExitUsage
+func UsageErrorf(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// Main runs the command tree rooted at root using the process' arguments and streams, returning the exit code
+func Main(root *Command, args []string) int {
+	ctx := &Context{
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+	return ctx.Execute(root, args)
+}
+
+// Execute runs the command tree rooted at root with the given arguments and returns the exit code
+func (c *Context) Execute(root *Command, args []string) int {
+	e := c.dispatch(root, args)
+	if e == nil || e == flag.ErrHelp {
+		return ExitOK
+	}
+
+	if ue, ok := e.(*usageError); ok {
+		if !ue.shown {
+			fmt.Fprintf(c.Stderr, "error: %s\nrun '%s -h' for usage\n", secret.Redact(ue.msg), strings.Join(c.path, " "))
+		}
+		return ExitUsage
+	}
+
+	// errors can come from anywhere, make sure they never leak a seed
+	fmt.Fprintf(c.Stderr, "error: %s\n", secret.Redact(e.Error()))
+	if coder, ok := e.(ExitCoder); ok {
+		return coder.ExitCode()
+	}
+	return ExitError
+}
+
+// dispatch walks down the command tree, parsing group-level flags along the way
+func (c *Context) dispatch(cmd *Command, args []string) error {
+	c.path = append(c.path, cmd.Name)
+	c.command = cmd
+	if cmd.Run != nil {
+		return cmd.Run(c, args)
+	}
+
+	fs := c.FlagSet()
+	e := c.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	args = fs.Args()
+	if len(args) == 0 {
+		c.printUsage()
+		return &usageError{msg: "missing subcommand", shown: true}
+	}
+
+	name := args[0]
+	if name == "help" {
+		return c.help(cmd, args[1:])
+	}
+	for _, sub := range cmd.Subcommands {
+		if sub.Name == name {
+			return c.dispatch(sub, args[1:])
+		}
+	}
+	return UsageErrorf("unknown command '%s'", name)
+}
+
+// help prints the usage of the command identified by the path in args
+func (c *Context) help(cmd *Command, args []string) error {
+	for _, name := range args {
+		var next *Command
+		for _, sub := range cmd.Subcommands {
+			if sub.Name == name {
+				next = sub
+			}
+		}
+		if next == nil {
+			return UsageErrorf("unknown command '%s'", name)
+		}
+		cmd = next
+		c.path = append(c.path, cmd.Name)
+	}
+	c.command = cmd
+	if cmd.Run != nil {
+		// leaf commands define their flags when run, so let them print their own usage
+		return cmd.Run(c, []string{"-h"})
+	}
+	c.printUsage()
+	return nil
+}
+
+// FlagSet returns a flag set for the running command with the global flags already defined on it, so they are
+// accepted both before and after the command name
+func (c *Context) FlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(strings.Join(c.path, " "), flag.ContinueOnError)
+	fs.SetOutput(c.Stderr)
+	profile.FlagVar(fs, &c.Network)
+	outputFlagVar(fs, &c.Output)
+	fs.Usage = func() {
+		c.printUsage()
+		if c.command.Run != nil {
+			fmt.Fprintf(c.Stderr, "\nflags:\n")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// Parse parses the command's arguments, a request for help is returned as flag.ErrHelp and any other failure as a
+// usage error
+func (c *Context) Parse(fs *flag.FlagSet, args []string) error {
+	e := fs.Parse(args)
+	if e == flag.ErrHelp {
+		return e
+	}
+	if e != nil {
+		return &usageError{msg: e.Error(), shown: true}
+	}
+	return c.checkOutput()
+}
+
+// printUsage prints the synopsis of the running command and lists its subcommands
+func (c *Context) printUsage() {
+	cmd := c.command
+	name := strings.Join(c.path, " ")
+	if cmd.Summary != "" {
+		fmt.Fprintf(c.Stderr, "%s - %s\n\n", name, cmd.Summary)
+	}
+	if cmd.Run != nil {
+		fmt.Fprintf(c.Stderr, "usage: %s [flags] %s\n", name, cmd.Usage)
+		return
+	}
+
+	fmt.Fprintf(c.Stderr, "usage: %s [--network <profile>] [--output text|json] <command> [flags]\n\ncommands:\n", name)
+	subs := append([]*Command{}, cmd.Subcommands...)
+	sort.Slice(subs, func(i, j int) bool { return subs[i].Name < subs[j].Name })
+	for _, sub := range subs {
+		fmt.Fprintf(c.Stderr, "    %-14s %s\n", sub.Name, sub.Summary)
+	}
+	fmt.Fprintf(c.Stderr, "\nrun '%s help <command>' for more information on a command\n", name)
+}
+
+// Profile loads the network profile selected with --network
+func (c *Context) Profile() (*profile.Profile, error) {
+	return profile.Load(c.Network)
+}
+
+// HTTPClient returns the client for requests to servers other than Horizon
+func (c *Context) HTTPClient() *http.Client {
+	if c.HTTP == nil {
+		return http.DefaultClient
+	}
+	return c.HTTP
+}
+
+// ReadLine prints the prompt to stderr and reads a single line from stdin without the trailing newline
+func (c *Context) ReadLine(prompt string) (string, error) {
+	if prompt != "" {
+		fmt.Fprint(c.Stderr, prompt)
+	}
+	if c.reader == nil {
+		c.reader = bufio.NewReader(c.Stdin)
+	}
+	line, e := c.reader.ReadString('\n')
+	if e != nil && (e != io.EOF || line == "") {
+		return "", e
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// ReadPassword prints the prompt to stderr and reads a line from the terminal without echoing it, falling back to
+// ReadLine when stdin is not a terminal so secrets can still be piped in
+func (c *Context) ReadPassword(prompt string) (string, error) {
+	f, ok := c.Stdin.(*os.File)
+	if !ok || !terminal.IsTerminal(int(f.Fd())) {
+		return c.ReadLine(prompt)
+	}
+
+	fmt.Fprint(c.Stderr, prompt)
+	password, e := terminal.ReadPassword(int(f.Fd()))
+	fmt.Fprintln(c.Stderr)
+	if e != nil {
+		return "", e
+	}
+	return string(password), nil
+}
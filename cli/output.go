@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// output formats selected with the global --output flag
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// outputFlagVar registers the output flag on the given flag set, storing the selected format in format
+func outputFlagVar(fs *flag.FlagSet, format *string) {
+	if *format == "" {
+		*format = OutputText
+	}
+	fs.StringVar(format, "output", *format, "output format: text for people or json for scripts, JSON goes to stdout and everything else to stderr")
+}
+
+// checkOutput returns a usage error when the selected output format is not supported
+func (c *Context) checkOutput() error {
+	switch c.Output {
+	case OutputText, OutputJSON:
+		return nil
+	}
+	return UsageErrorf("unknown output format '%s', expected %s or %s", c.Output, OutputText, OutputJSON)
+}
+
+// JSON reports whether the command should write its result as JSON
+func (c *Context) JSON() bool {
+	return c.Output == OutputJSON
+}
+
+// Info returns the writer for progress and diagnostic messages: stdout in text mode and stderr in JSON mode, so that
+// stdout only ever carries the JSON document
+func (c *Context) Info() io.Writer {
+	if c.JSON() {
+		return c.Stderr
+	}
+	return c.Stdout
+}
+
+// Emit writes the result of a command to stdout, as indented JSON in JSON mode and by calling text otherwise, text may
+// be nil for commands that have already printed everything in text mode
+func (c *Context) Emit(v interface{}, text func(w io.Writer)) error {
+	if !c.JSON() {
+		if text != nil {
+			text(c.Stdout)
+		}
+		return nil
+	}
+
+	return c.encode(v, "  ")
+}
+
+// EmitLine writes one compact JSON document per line in JSON mode, for commands that stream results, and calls text
+// otherwise
+func (c *Context) EmitLine(v interface{}, text func(w io.Writer)) error {
+	if !c.JSON() {
+		if text != nil {
+			text(c.Stdout)
+		}
+		return nil
+	}
+
+	return c.encode(v, "")
+}
+
+// encode writes v to stdout followed by a newline, URIs are common in the output so HTML characters are not escaped
+func (c *Context) encode(v interface{}, indent string) error {
+	enc := json.NewEncoder(c.Stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	e := enc.Encode(v)
+	if e != nil {
+		return fmt.Errorf("could not encode output as JSON: %s", e)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Command stellar is a single binary that bundles all the tools in this repository as subcommands.
+package main
+
+import (
+	"os"
+
+	"github.com/nikhilsaraf/stellar-go/accounts"
+	"github.com/nikhilsaraf/stellar-go/assets"
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/inflation"
+	"github.com/nikhilsaraf/stellar-go/keys"
+	"github.com/nikhilsaraf/stellar-go/offers"
+	"github.com/nikhilsaraf/stellar-go/signing"
+	"github.com/nikhilsaraf/stellar-go/transactions"
+)
+
+var root = &cli.Command{
+	Name:    "stellar",
+	Summary: "tools to work with Stellar accounts, assets, offers, transactions and SEP-7 URIs",
+	Subcommands: []*cli.Command{
+		{
+			Name:        "keys",
+			Summary:     "generate and check key pairs",
+			Subcommands: []*cli.Command{keys.GenCmd, keys.DeriveCmd, keys.CheckCmd, keys.ImportCmd, keys.ListCmd},
+		},
+		{
+			Name:        "account",
+			Summary:     "inspect, fund and configure accounts",
+			Subcommands: []*cli.Command{accounts.BalanceCmd, accounts.FundCmd, accounts.SetInflationCmd, accounts.MigrateCmd},
+		},
+		{
+			Name:        "asset",
+			Summary:     "work with issued assets",
+			Subcommands: []*cli.Command{assets.TrustCmd},
+		},
+		{
+			Name:        "offer",
+			Summary:     "inspect the order book and manage offers",
+			Subcommands: []*cli.Command{offers.ListCmd, offers.OrderbookCmd, offers.MakeCmd},
+		},
+		{
+			Name:        "tx",
+			Summary:     "send, stream, sign, collate and inspect transactions",
+			Subcommands: []*cli.Command{transactions.PayCmd, transactions.ListenCmd, signing.SignCmd, signing.CollateCmd, signing.InspectCmd},
+		},
+		{
+			Name:        "uri",
+			Summary:     "generate and handle SEP-7 URI requests",
+			Subcommands: []*cli.Command{signing.GenURICmd, signing.HandleURICmd, signing.SignURICmd, signing.VerifyURICmd},
+		},
+		{
+			Name:        "inflation",
+			Summary:     "run inflation",
+			Subcommands: []*cli.Command{inflation.RunCmd},
+		},
+	},
+}
+
+func main() {
+	os.Exit(cli.Main(root, os.Args[1:]))
+}
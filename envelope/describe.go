@@ -0,0 +1,339 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nikhilsaraf/stellar-go/asset"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+// Description is a readable view of a transaction envelope
+type Description struct {
+	Source     string        `json:"source_account"`
+	Sequence   int64         `json:"sequence"`
+	Fee        uint32        `json:"fee"`
+	TimeBounds *TimeBounds   `json:"time_bounds,omitempty"`
+	Memo       Memo          `json:"memo"`
+	Operations []Operation   `json:"operations"`
+	Signatures []Signature   `json:"signatures"`
+	Hashes     []NetworkHash `json:"hashes"`
+}
+
+// TimeBounds are the times between which the transaction is valid, as unix timestamps. A MaxTime of 0 means no limit.
+type TimeBounds struct {
+	MinTime uint64 `json:"min_time"`
+	MaxTime uint64 `json:"max_time"`
+}
+
+// Memo is the memo of the transaction, hashes are hex-encoded
+type Memo struct {
+	Type  string `json:"type"`
+	Value string `json:"value,omitempty"`
+}
+
+// Operation is a single operation, its fields are specific to its type and kept in a stable order
+type Operation struct {
+	Type   string
+	Source string
+	Fields []Field
+}
+
+// Field is a named value of an operation
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Signature is a signature on the envelope, SignedBy is set when it could be matched to one of the accounts used by
+// the transaction and Network names the passphrase it is valid for
+type Signature struct {
+	Hint      string `json:"hint"`
+	Signature string `json:"signature"`
+	SignedBy  string `json:"signed_by,omitempty"`
+	Network   string `json:"network,omitempty"`
+}
+
+// NetworkHash is the hash of the transaction, which is what gets signed, for one network passphrase
+type NetworkHash struct {
+	Network    string `json:"network"`
+	Passphrase string `json:"passphrase"`
+	Hash       string `json:"hash"`
+}
+
+// knownNetworks are the passphrases the hash is computed for
+var knownNetworks = []struct{ name, passphrase string }{
+	{"test", network.TestNetworkPassphrase},
+	{"public", network.PublicNetworkPassphrase},
+}
+
+// MarshalJSON encodes the operation as an object with the fields in order after the type and source
+func (op Operation) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	write := func(name string, value string) {
+		if buf.Len() > 1 {
+			buf.WriteString(",")
+		}
+		k, _ := json.Marshal(name)
+		v, _ := json.Marshal(value)
+		buf.Write(k)
+		buf.WriteString(":")
+		buf.Write(v)
+	}
+	write("type", op.Type)
+	if op.Source != "" {
+		write("source_account", op.Source)
+	}
+	for _, f := range op.Fields {
+		write(f.Name, f.Value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// Describe decodes the envelope into a Description
+func Describe(env *xdr.TransactionEnvelope) (*Description, error) {
+	tx := &env.Tx
+	d := &Description{
+		Source:     tx.SourceAccount.Address(),
+		Sequence:   int64(tx.SeqNum),
+		Fee:        uint32(tx.Fee),
+		Memo:       describeMemo(tx.Memo),
+		Operations: []Operation{},
+		Signatures: []Signature{},
+	}
+	if tx.TimeBounds != nil {
+		d.TimeBounds = &TimeBounds{MinTime: uint64(tx.TimeBounds.MinTime), MaxTime: uint64(tx.TimeBounds.MaxTime)}
+	}
+
+	signers := []string{d.Source}
+	for _, op := range tx.Operations {
+		described := describeOperation(op.Body)
+		if op.SourceAccount != nil {
+			described.Source = op.SourceAccount.Address()
+			signers = append(signers, described.Source)
+		}
+		d.Operations = append(d.Operations, described)
+	}
+
+	hashes := map[string][32]byte{}
+	for _, n := range knownNetworks {
+		hash, e := network.HashTransaction(tx, n.passphrase)
+		if e != nil {
+			return nil, fmt.Errorf("could not hash transaction: %s", e)
+		}
+		hashes[n.name] = hash
+		d.Hashes = append(d.Hashes, NetworkHash{Network: n.name, Passphrase: n.passphrase, Hash: hex.EncodeToString(hash[:])})
+	}
+
+	for _, sig := range env.Signatures {
+		s := Signature{
+			Hint:      hex.EncodeToString(sig.Hint[:]),
+			Signature: base64.StdEncoding.EncodeToString(sig.Signature),
+		}
+		s.SignedBy, s.Network = matchSignature(sig, signers, hashes)
+		d.Signatures = append(d.Signatures, s)
+	}
+	return d, nil
+}
+
+// matchSignature finds which of the candidate accounts made the signature and for which network
+func matchSignature(sig xdr.DecoratedSignature, candidates []string, hashes map[string][32]byte) (string, string) {
+	for _, address := range candidates {
+		kp, e := keypair.Parse(address)
+		if e != nil || kp.Hint() != [4]byte(sig.Hint) {
+			continue
+		}
+		for _, n := range knownNetworks {
+			hash := hashes[n.name]
+			if kp.Verify(hash[:], sig.Signature) == nil {
+				return address, n.name
+			}
+		}
+	}
+	return "", ""
+}
+
+func describeMemo(memo xdr.Memo) Memo {
+	switch memo.Type {
+	case xdr.MemoTypeMemoText:
+		return Memo{Type: "text", Value: *memo.Text}
+	case xdr.MemoTypeMemoId:
+		return Memo{Type: "id", Value: fmt.Sprintf("%d", uint64(*memo.Id))}
+	case xdr.MemoTypeMemoHash:
+		return Memo{Type: "hash", Value: hex.EncodeToString(memo.Hash[:])}
+	case xdr.MemoTypeMemoReturn:
+		return Memo{Type: "return", Value: hex.EncodeToString(memo.RetHash[:])}
+	}
+	return Memo{Type: "none"}
+}
+
+func describeOperation(body xdr.OperationBody) Operation {
+	op := Operation{}
+	add := func(name string, value string) {
+		op.Fields = append(op.Fields, Field{Name: name, Value: value})
+	}
+
+	switch body.Type {
+	case xdr.OperationTypeCreateAccount:
+		op.Type = "create_account"
+		add("destination", body.CreateAccountOp.Destination.Address())
+		add("starting_balance", amount.String(body.CreateAccountOp.StartingBalance))
+	case xdr.OperationTypePayment:
+		op.Type = "payment"
+		add("destination", body.PaymentOp.Destination.Address())
+		add("asset", asset.String(body.PaymentOp.Asset))
+		add("amount", amount.String(body.PaymentOp.Amount))
+	case xdr.OperationTypePathPayment:
+		p := body.PathPaymentOp
+		op.Type = "path_payment"
+		add("destination", p.Destination.Address())
+		add("send_asset", asset.String(p.SendAsset))
+		add("send_max", amount.String(p.SendMax))
+		add("dest_asset", asset.String(p.DestAsset))
+		add("dest_amount", amount.String(p.DestAmount))
+		for i, a := range p.Path {
+			add(fmt.Sprintf("path_%d", i), asset.String(a))
+		}
+	case xdr.OperationTypeManageOffer:
+		o := body.ManageOfferOp
+		op.Type = "manage_offer"
+		add("selling", asset.String(o.Selling))
+		add("buying", asset.String(o.Buying))
+		add("amount", amount.String(o.Amount))
+		add("price", describePrice(o.Price))
+		add("offer_id", fmt.Sprintf("%d", uint64(o.OfferId)))
+	case xdr.OperationTypeCreatePassiveOffer:
+		o := body.CreatePassiveOfferOp
+		op.Type = "create_passive_offer"
+		add("selling", asset.String(o.Selling))
+		add("buying", asset.String(o.Buying))
+		add("amount", amount.String(o.Amount))
+		add("price", describePrice(o.Price))
+	case xdr.OperationTypeSetOptions:
+		o := body.SetOptionsOp
+		op.Type = "set_options"
+		if o.InflationDest != nil {
+			add("inflation_dest", o.InflationDest.Address())
+		}
+		addUint32 := func(name string, v *xdr.Uint32) {
+			if v != nil {
+				add(name, fmt.Sprintf("%d", uint32(*v)))
+			}
+		}
+		addUint32("clear_flags", o.ClearFlags)
+		addUint32("set_flags", o.SetFlags)
+		addUint32("master_weight", o.MasterWeight)
+		addUint32("low_threshold", o.LowThreshold)
+		addUint32("med_threshold", o.MedThreshold)
+		addUint32("high_threshold", o.HighThreshold)
+		if o.HomeDomain != nil {
+			add("home_domain", string(*o.HomeDomain))
+		}
+		if o.Signer != nil {
+			add("signer_key", o.Signer.Key.Address())
+			add("signer_weight", fmt.Sprintf("%d", uint32(o.Signer.Weight)))
+		}
+	case xdr.OperationTypeChangeTrust:
+		op.Type = "change_trust"
+		add("asset", asset.String(body.ChangeTrustOp.Line))
+		add("limit", amount.String(body.ChangeTrustOp.Limit))
+	case xdr.OperationTypeAllowTrust:
+		o := body.AllowTrustOp
+		op.Type = "allow_trust"
+		add("trustor", o.Trustor.Address())
+		switch o.Asset.Type {
+		case xdr.AssetTypeAssetTypeCreditAlphanum4:
+			add("asset_code", string(bytes.TrimRight(o.Asset.AssetCode4[:], "\x00")))
+		case xdr.AssetTypeAssetTypeCreditAlphanum12:
+			add("asset_code", string(bytes.TrimRight(o.Asset.AssetCode12[:], "\x00")))
+		}
+		add("authorize", fmt.Sprintf("%t", o.Authorize))
+	case xdr.OperationTypeAccountMerge:
+		op.Type = "account_merge"
+		add("destination", body.Destination.Address())
+	case xdr.OperationTypeInflation:
+		op.Type = "inflation"
+	case xdr.OperationTypeManageData:
+		o := body.ManageDataOp
+		op.Type = "manage_data"
+		add("name", string(o.DataName))
+		if o.DataValue != nil {
+			add("value", base64.StdEncoding.EncodeToString(*o.DataValue))
+		} else {
+			add("value", "(deleted)")
+		}
+	case xdr.OperationTypeBumpSequence:
+		op.Type = "bump_sequence"
+		add("bump_to", fmt.Sprintf("%d", int64(body.BumpSequenceOp.BumpTo)))
+	default:
+		op.Type = fmt.Sprintf("unknown (%d)", int32(body.Type))
+	}
+	return op
+}
+
+func describePrice(p xdr.Price) string {
+	if p.D == 0 {
+		return fmt.Sprintf("%d/0", p.N)
+	}
+	return fmt.Sprintf("%d/%d (%.7f)", p.N, p.D, float64(p.N)/float64(p.D))
+}
+
+// WriteText writes the description in a readable text format
+func (d *Description) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "source account: %s\n", d.Source)
+	fmt.Fprintf(w, "sequence:       %d\n", d.Sequence)
+	fmt.Fprintf(w, "fee:            %d stroops\n", d.Fee)
+	if d.TimeBounds != nil {
+		fmt.Fprintf(w, "valid after:    %s\n", formatTime(d.TimeBounds.MinTime))
+		fmt.Fprintf(w, "valid until:    %s\n", formatTime(d.TimeBounds.MaxTime))
+	} else {
+		fmt.Fprintf(w, "time bounds:    none\n")
+	}
+	if d.Memo.Value != "" {
+		fmt.Fprintf(w, "memo:           %s %q\n", d.Memo.Type, d.Memo.Value)
+	} else {
+		fmt.Fprintf(w, "memo:           %s\n", d.Memo.Type)
+	}
+
+	fmt.Fprintf(w, "\noperations (%d):\n", len(d.Operations))
+	for i, op := range d.Operations {
+		fmt.Fprintf(w, "  %d. %s\n", i+1, op.Type)
+		if op.Source != "" {
+			fmt.Fprintf(w, "       %-18s %s\n", "source_account:", op.Source)
+		}
+		for _, f := range op.Fields {
+			fmt.Fprintf(w, "       %-18s %s\n", f.Name+":", f.Value)
+		}
+	}
+
+	fmt.Fprintf(w, "\nsignatures (%d):\n", len(d.Signatures))
+	for _, s := range d.Signatures {
+		if s.SignedBy != "" {
+			fmt.Fprintf(w, "  hint %s: signed by %s for the %s network\n", s.Hint, s.SignedBy, s.Network)
+		} else {
+			fmt.Fprintf(w, "  hint %s: unknown signer\n", s.Hint)
+		}
+	}
+
+	fmt.Fprintf(w, "\nhashes:\n")
+	for _, h := range d.Hashes {
+		fmt.Fprintf(w, "  %-7s %s\n", h.Network+":", h.Hash)
+	}
+}
+
+func formatTime(t uint64) string {
+	if t == 0 {
+		return "unbounded"
+	}
+	return fmt.Sprintf("%d (%s)", t, time.Unix(int64(t), 0).UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2018 Lightyear.io
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package envelope decodes base64-encoded transaction envelopes and combines the signatures of several copies of the
+// same transaction.
+package envelope
+
+import (
+	"bytes"
+	"fmt"
+
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/xdr"
+)
+
+// Decode decodes the transaction envelope from a base64 string into a TransactionEnvelopeBuilder so it can be mutated
+func Decode(encodedXdr string) (*b.TransactionEnvelopeBuilder, error) {
+	var decoded xdr.TransactionEnvelope
+	e := xdr.SafeUnmarshalBase64(encodedXdr, &decoded)
+	if e != nil {
+		return nil, fmt.Errorf("could not decode transaction envelope: %s", e)
+	}
+
+	txEnvelopeBuilder := b.TransactionEnvelopeBuilder{E: &decoded}
+	txEnvelopeBuilder.Init()
+	return &txEnvelopeBuilder, nil
+}
+
+// Collate takes a list of base64-encoded envelopes of the same transaction and combines their signatures into a single
+// envelope, as done by a multi-signature coordination service. Every envelope must contain the same transaction and
+// signatures that appear in more than one envelope are only included once.
+func Collate(xdrList []string) (string, error) {
+	if len(xdrList) == 0 {
+		return "", fmt.Errorf("no transactions to collate")
+	}
+
+	collated, e := Decode(xdrList[0])
+	if e != nil {
+		return "", e
+	}
+	txBytes, e := marshal(collated.E.Tx)
+	if e != nil {
+		return "", e
+	}
+	signatures := collated.E.Signatures
+	seen := map[string]bool{}
+	collated.E.Signatures = nil
+	addSignatures := func(sigs []xdr.DecoratedSignature) {
+		for _, sig := range sigs {
+			key := string(sig.Hint[:]) + string(sig.Signature)
+			if !seen[key] {
+				seen[key] = true
+				collated.E.Signatures = append(collated.E.Signatures, sig)
+			}
+		}
+	}
+	addSignatures(signatures)
+
+	for i, encoded := range xdrList[1:] {
+		tx, e := Decode(encoded)
+		if e != nil {
+			return "", fmt.Errorf("transaction %d: %s", i+2, e)
+		}
+		otherBytes, e := marshal(tx.E.Tx)
+		if e != nil {
+			return "", e
+		}
+		if !bytes.Equal(txBytes, otherBytes) {
+			return "", fmt.Errorf("transaction %d is not the same transaction as the first one", i+2)
+		}
+		addSignatures(tx.E.Signatures)
+	}
+
+	collatedXdr, e := collated.Base64()
+	if e != nil {
+		return "", fmt.Errorf("failed to convert to base64: %s", e)
+	}
+	return collatedXdr, nil
+}
+
+func marshal(tx xdr.Transaction) ([]byte, error) {
+	var buf bytes.Buffer
+	_, e := xdr.Marshal(&buf, tx)
+	if e != nil {
+		return nil, fmt.Errorf("could not encode transaction: %s", e)
+	}
+	return buf.Bytes(), nil
+}
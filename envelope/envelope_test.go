@@ -0,0 +1,118 @@
+package envelope
+
+import (
+	"strings"
+	"testing"
+
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/keypair"
+)
+
+var (
+	source      = keypair.MustParse("SBPOVRVKTTV7W3IOX2FJPSMPCJ5L2WU2YKTP3HCLYPXNI5MDIGREVNYC").(*keypair.Full)
+	cosigner, _ = keypair.Random()
+	destination = "GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO"
+)
+
+// signedPayment builds a payment from source with the sequence number and returns its envelope signed by the seeds
+func signedPayment(t *testing.T, sequence uint64, seeds ...string) string {
+	txn, e := b.Transaction(
+		b.SourceAccount{AddressOrSeed: source.Address()},
+		b.Sequence{Sequence: sequence},
+		b.TestNetwork,
+		b.Payment(
+			b.Destination{AddressOrSeed: destination},
+			b.NativeAmount{Amount: "10"},
+		),
+	)
+	if e != nil {
+		t.Fatal(e)
+	}
+	env, e := txn.Sign(seeds...)
+	if e != nil {
+		t.Fatal(e)
+	}
+	encoded, e := env.Base64()
+	if e != nil {
+		t.Fatal(e)
+	}
+	return encoded
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         string
+		signatures int
+		invalid    bool
+	}{
+		{name: "unsigned", in: signedPayment(t, 5), signatures: 0},
+		{name: "signed", in: signedPayment(t, 5, source.Seed()), signatures: 1},
+		{name: "signed twice", in: signedPayment(t, 5, source.Seed(), cosigner.Seed()), signatures: 2},
+		{name: "empty", in: "", invalid: true},
+		{name: "not base64", in: "not an envelope!", invalid: true},
+		{name: "truncated", in: "AAAAA", invalid: true},
+	}
+	for _, test := range tests {
+		txn, e := Decode(test.in)
+		if test.invalid {
+			if e == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+		if e != nil {
+			t.Errorf("%s: %s", test.name, e)
+			continue
+		}
+		if got := txn.E.Tx.SourceAccount.Address(); got != source.Address() {
+			t.Errorf("%s: source %s, want %s", test.name, got, source.Address())
+		}
+		if txn.E.Tx.SeqNum != 5 {
+			t.Errorf("%s: sequence %d, want 5", test.name, txn.E.Tx.SeqNum)
+		}
+		if len(txn.E.Signatures) != test.signatures {
+			t.Errorf("%s: got %d signatures, want %d", test.name, len(txn.E.Signatures), test.signatures)
+		}
+	}
+}
+
+func TestCollate(t *testing.T) {
+	bySource := signedPayment(t, 5, source.Seed())
+	byCosigner := signedPayment(t, 5, cosigner.Seed())
+	tests := []struct {
+		name       string
+		in         []string
+		signatures int
+		err        string
+	}{
+		{name: "two signers", in: []string{bySource, byCosigner}, signatures: 2},
+		{name: "duplicate signature", in: []string{bySource, bySource, byCosigner}, signatures: 2},
+		{name: "signed and unsigned", in: []string{signedPayment(t, 5), bySource}, signatures: 1},
+		{name: "single envelope", in: []string{bySource}, signatures: 1},
+		{name: "none", in: nil, err: "no transactions"},
+		{name: "other transaction", in: []string{bySource, signedPayment(t, 6, cosigner.Seed())}, err: "transaction 2 is not the same transaction"},
+		{name: "bad envelope", in: []string{bySource, "AAAAA"}, err: "transaction 2"},
+	}
+	for _, test := range tests {
+		collated, e := Collate(test.in)
+		if test.err != "" {
+			if e == nil || !strings.Contains(e.Error(), test.err) {
+				t.Errorf("%s: got error %v, want %q", test.name, e, test.err)
+			}
+			continue
+		}
+		if e != nil {
+			t.Errorf("%s: %s", test.name, e)
+			continue
+		}
+		txn, e := Decode(collated)
+		if e != nil {
+			t.Errorf("%s: %s", test.name, e)
+			continue
+		}
+		if len(txn.E.Signatures) != test.signatures {
+			t.Errorf("%s: got %d signatures, want %d", test.name, len(txn.E.Signatures), test.signatures)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// Package federation resolves federation addresses such as bob*example.com (SEP-2) into account IDs and the memos that
+// payments to them must carry, using the federation server named in the domain's stellar.toml.
+package federation
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsaraf/stellar-go/stellartoml"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// maxMemoText is the longest text memo in bytes
+const maxMemoText = 28
+
+// Record is the answer of a federation server, memo fields are empty when payments need no memo
+type Record struct {
+	StellarAddress string `json:"stellar_address"`
+	AccountID      string `json:"account_id"`
+	MemoType       string `json:"memo_type,omitempty"`
+	Memo           string `json:"memo,omitempty"`
+}
+
+// UnmarshalJSON accepts the memo as a string or as a number, which some servers use for id memos
+func (r *Record) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		StellarAddress string          `json:"stellar_address"`
+		AccountID      string          `json:"account_id"`
+		MemoType       string          `json:"memo_type"`
+		Memo           json.RawMessage `json:"memo"`
+	}
+	e := json.Unmarshal(data, &raw)
+	if e != nil {
+		return e
+	}
+	*r = Record{StellarAddress: raw.StellarAddress, AccountID: raw.AccountID, MemoType: raw.MemoType}
+	if len(raw.Memo) > 0 && string(raw.Memo) != "null" {
+		e = json.Unmarshal(raw.Memo, &r.Memo)
+		if e != nil {
+			r.Memo = string(raw.Memo)
+		}
+	}
+	return nil
+}
+
+// IsAddress reports whether s is a federation address rather than an account ID
+func IsAddress(s string) bool {
+	return strings.Contains(s, "*")
+}
+
+// split returns the name and domain of a federation address, the name may itself contain a *
+func split(address string) (string, string, error) {
+	i := strings.LastIndex(address, "*")
+	name, domain := address[:i], address[i+1:]
+	if name == "" || domain == "" || strings.ContainsAny(domain, "/?#@ ") {
+		return "", "", fmt.Errorf("'%s' is not a federation address of the form name*domain.com", address)
+	}
+	return name, domain, nil
+}
+
+// Resolve returns the record for s, which is either an account ID that is returned as is or a federation address that
+// is looked up. client may be nil to use http.DefaultClient.
+func Resolve(client stellartoml.HTTP, s string) (*Record, error) {
+	if !IsAddress(s) {
+		return &Record{AccountID: s}, nil
+	}
+	return Lookup(client, s)
+}
+
+// Lookup resolves a federation address with the federation server of its domain
+func Lookup(client stellartoml.HTTP, address string) (*Record, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	_, domain, e := split(address)
+	if e != nil {
+		return nil, e
+	}
+	toml, e := stellartoml.Get(client, domain)
+	if e != nil {
+		return nil, e
+	}
+	if toml.FederationServer == "" {
+		return nil, fmt.Errorf("%s does not run a federation server, its stellar.toml has no FEDERATION_SERVER", domain)
+	}
+
+	u, e := url.Parse(toml.FederationServer)
+	if e != nil || u.Host == "" {
+		return nil, fmt.Errorf("the FEDERATION_SERVER '%s' of %s is not a URL", toml.FederationServer, domain)
+	}
+	q := u.Query()
+	q.Set("type", "name")
+	q.Set("q", address)
+	u.RawQuery = q.Encode()
+
+	resp, e := client.Get(u.String())
+	if e != nil {
+		return nil, fmt.Errorf("could not reach the federation server of %s: %s", domain, e)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("the federation server of %s does not know %s", domain, address)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the federation server of %s returned status %d for %s", domain, resp.StatusCode, address)
+	}
+
+	var record Record
+	e = json.NewDecoder(resp.Body).Decode(&record)
+	if e != nil {
+		return nil, fmt.Errorf("invalid answer from the federation server of %s: %s", domain, e)
+	}
+	_, e = strkey.Decode(strkey.VersionByteAccountID, record.AccountID)
+	if e != nil {
+		return nil, fmt.Errorf("the federation server of %s returned an invalid account ID '%s' for %s", domain, record.AccountID, address)
+	}
+	_, e = record.memo()
+	if e != nil {
+		return nil, fmt.Errorf("the federation server of %s returned an invalid memo for %s: %s", domain, address, e)
+	}
+	if record.StellarAddress == "" {
+		record.StellarAddress = address
+	}
+	return &record, nil
+}
+
+// MemoMutator returns the memo to attach to a transaction paying the record's account, combining the memo the
+// federation server requires with the memo text the user asked for. It returns nil when there is no memo, and an error
+// when both are given and differ.
+func (r *Record) MemoMutator(text string) (b.TransactionMutator, error) {
+	required, e := r.memo()
+	if e != nil {
+		return nil, e
+	}
+	if required == nil {
+		if text == "" {
+			return nil, nil
+		}
+		return b.MemoText{Value: text}, nil
+	}
+	if text != "" && (r.MemoType != "text" || r.Memo != text) {
+		return nil, fmt.Errorf("payments to %s need the %s memo '%s', which conflicts with the memo '%s'", r.StellarAddress, r.MemoType, r.Memo, text)
+	}
+	return required, nil
+}
+
+// memo converts the memo returned by the federation server, nil when there is none
+func (r *Record) memo() (b.TransactionMutator, error) {
+	switch r.MemoType {
+	case "", "none":
+		return nil, nil
+	case "text":
+		if len(r.Memo) > maxMemoText {
+			return nil, fmt.Errorf("text memo '%s' is longer than %d bytes", r.Memo, maxMemoText)
+		}
+		return b.MemoText{Value: r.Memo}, nil
+	case "id":
+		id, e := strconv.ParseUint(r.Memo, 10, 64)
+		if e != nil {
+			return nil, fmt.Errorf("id memo '%s' is not a 64 bit unsigned integer", r.Memo)
+		}
+		return b.MemoID{Value: id}, nil
+	case "hash":
+		raw, e := base64.StdEncoding.DecodeString(r.Memo)
+		if e != nil || len(raw) != 32 {
+			return nil, fmt.Errorf("hash memo '%s' is not 32 base64 encoded bytes", r.Memo)
+		}
+		var hash xdr.Hash
+		copy(hash[:], raw)
+		return b.MemoHash{Value: hash}, nil
+	}
+	return nil, fmt.Errorf("unknown memo type '%s'", r.MemoType)
+}
+
+// String describes the record, e.g. "bob*example.com (GABC..., memo id 123)"
+func (r *Record) String() string {
+	if r.StellarAddress == "" {
+		return r.AccountID
+	}
+	s := r.StellarAddress + " (" + r.AccountID
+	if r.MemoType != "" && r.MemoType != "none" {
+		s += ", memo " + r.MemoType + " " + r.Memo
+	}
+	return s + ")"
+}
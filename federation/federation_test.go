@@ -0,0 +1,147 @@
+package federation_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsaraf/stellar-go/federation"
+	"github.com/nikhilsaraf/stellar-go/horizontest"
+)
+
+const (
+	bob   = "GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO"
+	alice = "GD7ACHBPHSC5OJMJZZBXA7Z5IAUFTH6E6XVLNBPASDQYJ7LO5UIYBDQW"
+)
+
+func TestIsAddress(t *testing.T) {
+	if !federation.IsAddress("bob*example.com") {
+		t.Error("bob*example.com is a federation address")
+	}
+	if federation.IsAddress(bob) {
+		t.Errorf("%s is not a federation address", bob)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	s.AddFederation(federation.Record{StellarAddress: "bob*example.com", AccountID: bob, MemoType: "id", Memo: "42"})
+	s.AddFederation(federation.Record{StellarAddress: "alice*example.com", AccountID: alice})
+	s.AddFederation(federation.Record{StellarAddress: "alice@mail.com*example.com", AccountID: alice, MemoType: "text", Memo: "savings"})
+
+	tests := []struct {
+		in   string
+		want federation.Record
+	}{
+		{bob, federation.Record{AccountID: bob}},
+		{"bob*example.com", federation.Record{StellarAddress: "bob*example.com", AccountID: bob, MemoType: "id", Memo: "42"}},
+		{"alice*example.com", federation.Record{StellarAddress: "alice*example.com", AccountID: alice}},
+		{"alice@mail.com*example.com", federation.Record{StellarAddress: "alice@mail.com*example.com", AccountID: alice, MemoType: "text", Memo: "savings"}},
+	}
+	for _, test := range tests {
+		record, e := federation.Resolve(s.HTTPClient(), test.in)
+		if e != nil {
+			t.Errorf("%s: %s", test.in, e)
+			continue
+		}
+		if *record != test.want {
+			t.Errorf("%s: got %+v, want %+v", test.in, *record, test.want)
+		}
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	s.AddFederation(federation.Record{StellarAddress: "bad-account*example.com", AccountID: "GABC"})
+	s.AddFederation(federation.Record{StellarAddress: "bad-memo*example.com", AccountID: bob, MemoType: "id", Memo: "forty-two"})
+	s.AddFederation(federation.Record{StellarAddress: "long-memo*example.com", AccountID: bob, MemoType: "text", Memo: strings.Repeat("x", 29)})
+
+	tests := []struct {
+		in  string
+		err string
+	}{
+		{"nobody*example.com", "does not know"},
+		{"bad-account*example.com", "invalid account ID"},
+		{"bad-memo*example.com", "invalid memo"},
+		{"long-memo*example.com", "invalid memo"},
+		{"bob*", "not a federation address"},
+		{"*example.com", "not a federation address"},
+		{"bob*example.com/path", "not a federation address"},
+	}
+	for _, test := range tests {
+		_, e := federation.Resolve(s.HTTPClient(), test.in)
+		if e == nil || !strings.Contains(e.Error(), test.err) {
+			t.Errorf("%s: got error %v, want %q", test.in, e, test.err)
+		}
+	}
+}
+
+func TestRecordUnmarshal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want federation.Record
+	}{
+		{`{"stellar_address":"bob*example.com","account_id":"` + bob + `","memo_type":"id","memo":"42"}`, federation.Record{StellarAddress: "bob*example.com", AccountID: bob, MemoType: "id", Memo: "42"}},
+		{`{"stellar_address":"bob*example.com","account_id":"` + bob + `","memo_type":"id","memo":42}`, federation.Record{StellarAddress: "bob*example.com", AccountID: bob, MemoType: "id", Memo: "42"}},
+		{`{"account_id":"` + bob + `","memo":null}`, federation.Record{AccountID: bob}},
+	}
+	for _, test := range tests {
+		var record federation.Record
+		e := json.Unmarshal([]byte(test.in), &record)
+		if e != nil {
+			t.Errorf("%s: %s", test.in, e)
+			continue
+		}
+		if record != test.want {
+			t.Errorf("%s: got %+v, want %+v", test.in, record, test.want)
+		}
+	}
+}
+
+func TestMemoMutator(t *testing.T) {
+	tests := []struct {
+		record federation.Record
+		text   string
+		none   bool
+		err    bool
+	}{
+		{record: federation.Record{AccountID: bob}, none: true},
+		{record: federation.Record{AccountID: bob}, text: "rent"},
+		{record: federation.Record{AccountID: bob, MemoType: "id", Memo: "42"}},
+		{record: federation.Record{AccountID: bob, MemoType: "id", Memo: "42"}, text: "rent", err: true},
+		{record: federation.Record{AccountID: bob, MemoType: "text", Memo: "rent"}, text: "rent"},
+		{record: federation.Record{AccountID: bob, MemoType: "text", Memo: "rent"}, text: "food", err: true},
+		{record: federation.Record{AccountID: bob, MemoType: "hash", Memo: "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="}},
+		{record: federation.Record{AccountID: bob, MemoType: "hash", Memo: "AAAA"}, err: true},
+		{record: federation.Record{AccountID: bob, MemoType: "return", Memo: "42"}, err: true},
+	}
+	for _, test := range tests {
+		memo, e := test.record.MemoMutator(test.text)
+		if test.err {
+			if e == nil {
+				t.Errorf("%+v with %q: expected an error", test.record, test.text)
+			}
+			continue
+		}
+		if e != nil {
+			t.Errorf("%+v with %q: %s", test.record, test.text, e)
+			continue
+		}
+		if (memo == nil) != test.none {
+			t.Errorf("%+v with %q: got memo %v", test.record, test.text, memo)
+		}
+	}
+}
+
+func TestRecordString(t *testing.T) {
+	r := federation.Record{StellarAddress: "bob*example.com", AccountID: bob, MemoType: "id", Memo: "123"}
+	if want := "bob*example.com (" + bob + ", memo id 123)"; r.String() != want {
+		t.Errorf("got %s, want %s", r.String(), want)
+	}
+	r = federation.Record{AccountID: bob}
+	if r.String() != bob {
+		t.Errorf("got %s, want %s", r.String(), bob)
+	}
+}
@@ -0,0 +1,392 @@
+package horizontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/xdr"
+)
+
+// handler routes the requests to the mocked endpoints
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.serveRoot)
+	mux.HandleFunc("/accounts/", s.serveAccounts)
+	mux.HandleFunc("/order_book", s.serveOrderBook)
+	mux.HandleFunc("/transactions", s.serveTransactions)
+	mux.HandleFunc("/transactions/", s.serveTransaction)
+	mux.HandleFunc("/friendbot", s.serveFriendbot)
+	mux.HandleFunc("/fee_stats", s.serveFeeStats)
+	mux.HandleFunc("/.well-known/stellar.toml", s.serveStellarTOML)
+	mux.HandleFunc("/federation", s.serveFederation)
+	mux.HandleFunc("/callback", s.serveCallback)
+	return mux
+}
+
+func (s *Server) serveRoot(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		writeProblem(w, http.StatusNotFound, "Resource Missing", "The resource at the url requested was not found.", nil)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"network_passphrase": s.Passphrase,
+		"core_latest_ledger": s.currentLedger(),
+	})
+}
+
+// serveAccounts handles /accounts/{id}, /accounts/{id}/offers and /accounts/{id}/payments
+func (s *Server) serveAccounts(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/accounts/"), "/"), "/")
+	address := parts[0]
+	switch {
+	case len(parts) == 1:
+		acct, ok := s.Account(address)
+		if !ok {
+			writeNotFound(w)
+			return
+		}
+		writeJSON(w, http.StatusOK, acct)
+	case len(parts) == 2 && parts[1] == "offers":
+		s.serveOffers(w, address)
+	case len(parts) == 2 && parts[1] == "payments":
+		if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+			s.streamPayments(w, r, address)
+			return
+		}
+		s.servePayments(w, r, address)
+	default:
+		writeNotFound(w)
+	}
+}
+
+func (s *Server) serveOffers(w http.ResponseWriter, address string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.accounts[address]; !ok {
+		writeNotFound(w)
+		return
+	}
+
+	page := horizon.OffersPage{}
+	for _, offer := range s.sortedOffers() {
+		if offer.Seller == address {
+			page.Embedded.Records = append(page.Embedded.Records, *offer)
+		}
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) servePayments(w http.ResponseWriter, r *http.Request, address string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := []horizon.Payment{}
+	for _, payment := range s.paymentsAfter(address, r.URL.Query().Get("cursor")) {
+		records = append(records, payment)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"_embedded": map[string]interface{}{"records": records},
+	})
+}
+
+// streamPayments sends the payments after the cursor as server-sent events and then every new payment until the
+// client disconnects
+func (s *Server) streamPayments(w http.ResponseWriter, r *http.Request, address string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeProblem(w, http.StatusNotAcceptable, "Not Acceptable", "streaming is not supported", nil)
+		return
+	}
+
+	ch := make(chan horizon.Payment, 100)
+	s.mu.Lock()
+	backlog := s.paymentsAfter(address, r.URL.Query().Get("cursor"))
+	s.subscribers[ch] = address
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, ch)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 1000\nevent: open\ndata: \"hello\"\n\n")
+	for _, payment := range backlog {
+		writeEvent(w, payment)
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case payment := <-ch:
+			writeEvent(w, payment)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, payment horizon.Payment) {
+	data, _ := json.Marshal(payment)
+	fmt.Fprintf(w, "id: %s\ndata: %s\n\n", payment.PagingToken, data)
+}
+
+// paymentsAfter returns the payments involving address after the cursor, must be called with the lock held
+func (s *Server) paymentsAfter(address string, cursor string) []horizon.Payment {
+	after, _ := strconv.ParseInt(cursor, 10, 64)
+	if cursor == "now" {
+		after = s.nextID
+	}
+	payments := []horizon.Payment{}
+	for _, payment := range s.payments {
+		token, _ := strconv.ParseInt(payment.PagingToken, 10, 64)
+		if token > after && (payment.From == address || payment.To == address) {
+			payments = append(payments, payment)
+		}
+	}
+	return payments
+}
+
+// serveOrderBook aggregates the open offers for the pair of assets in the query into price levels
+func (s *Server) serveOrderBook(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	selling := horizon.Asset{Type: q.Get("selling_asset_type"), Code: q.Get("selling_asset_code"), Issuer: q.Get("selling_asset_issuer")}
+	buying := horizon.Asset{Type: q.Get("buying_asset_type"), Code: q.Get("buying_asset_code"), Issuer: q.Get("buying_asset_issuer")}
+	if selling.Type == "" || buying.Type == "" {
+		writeProblem(w, http.StatusBadRequest, "Bad Request", "selling_asset_type and buying_asset_type are required", nil)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	summary := horizon.OrderBookSummary{Selling: selling, Buying: buying}
+	asks := map[string]*horizon.PriceLevel{}
+	bids := map[string]*horizon.PriceLevel{}
+	for _, offer := range s.sortedOffers() {
+		if assetKey(offer.Selling) == assetKey(selling) && assetKey(offer.Buying) == assetKey(buying) {
+			addLevel(asks, offer.PriceR, offer.Price, offer.Amount)
+		} else if assetKey(offer.Selling) == assetKey(buying) && assetKey(offer.Buying) == assetKey(selling) {
+			// bids are quoted in the counter asset, so the price is inverted
+			price := horizon.Price{N: offer.PriceR.D, D: offer.PriceR.N}
+			addLevel(bids, price, fmt.Sprintf("%.7f", float64(price.N)/float64(price.D)), offer.Amount)
+		}
+	}
+	summary.Asks = sortedLevels(asks, true)
+	summary.Bids = sortedLevels(bids, false)
+	writeJSON(w, http.StatusOK, summary)
+}
+
+func addLevel(levels map[string]*horizon.PriceLevel, priceR horizon.Price, price string, amt string) {
+	level, ok := levels[price]
+	if !ok {
+		level = &horizon.PriceLevel{PriceR: priceR, Price: price, Amount: "0"}
+		levels[price] = level
+	}
+	level.Amount = amount.String(amount.MustParse(level.Amount) + amount.MustParse(amt))
+}
+
+func sortedLevels(levels map[string]*horizon.PriceLevel, ascending bool) []horizon.PriceLevel {
+	sorted := []horizon.PriceLevel{}
+	for _, level := range levels {
+		sorted = append(sorted, *level)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		pi := float64(sorted[i].PriceR.N) / float64(sorted[i].PriceR.D)
+		pj := float64(sorted[j].PriceR.N) / float64(sorted[j].PriceR.D)
+		if ascending {
+			return pi < pj
+		}
+		return pi > pj
+	})
+	return sorted
+}
+
+func (s *Server) serveTransactions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeProblem(w, http.StatusMethodNotAllowed, "Method Not Allowed", "only POST is supported by the mock", nil)
+		return
+	}
+	e := r.ParseForm()
+	if e != nil {
+		writeProblem(w, http.StatusBadRequest, "Transaction Malformed", e.Error(), nil)
+		return
+	}
+
+	held, landing := s.holdLate(r.PostForm.Get("tx"))
+	if landing != "" {
+		s.submit(landing)
+	}
+	if held {
+		writeProblem(w, http.StatusGatewayTimeout, "Timeout", "Your request timed out before completing.", nil)
+		return
+	}
+	success, problem := s.submit(r.PostForm.Get("tx"))
+	if problem == nil && s.takeTimeout(true) {
+		problem = newProblem(http.StatusGatewayTimeout, "Timeout", "Your request timed out before completing.", nil)
+	}
+	if problem != nil {
+		writeJSON(w, problem.Status, problem)
+		return
+	}
+	writeJSON(w, http.StatusOK, success)
+}
+
+// serveTransaction handles /transactions/{hash} for the transactions accepted so far
+func (s *Server) serveTransaction(w http.ResponseWriter, r *http.Request) {
+	hash := strings.Trim(strings.TrimPrefix(r.URL.Path, "/transactions/"), "/")
+	for _, tx := range s.Transactions() {
+		if tx.Hash == hash {
+			writeJSON(w, http.StatusOK, tx)
+			return
+		}
+	}
+	writeNotFound(w)
+}
+
+// serveFeeStats reports the current minimum fee as every percentile of the accepted fees
+func (s *Server) serveFeeStats(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	fee := fmt.Sprintf("%d", s.minFee)
+	stats := map[string]string{
+		"last_ledger":          fmt.Sprintf("%d", s.ledger-1),
+		"last_ledger_base_fee": fmt.Sprintf("%d", BaseFee),
+		"min_accepted_fee":     fee,
+		"mode_accepted_fee":    fee,
+	}
+	s.mu.Unlock()
+	for _, p := range []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 95, 99} {
+		stats[fmt.Sprintf("p%d_accepted_fee", p)] = fee
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// serveFriendbot creates and funds the account in the addr query parameter
+func (s *Server) serveFriendbot(w http.ResponseWriter, r *http.Request) {
+	address := r.URL.Query().Get("addr")
+	if _, e := parseAddress(address); e != nil {
+		writeProblem(w, http.StatusBadRequest, "Bad Request", "invalid addr: "+e.Error(), nil)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.accounts[address]; ok {
+		writeProblem(w, http.StatusBadRequest, "Transaction Failed", "The transaction failed when submitted to the stellar network.",
+			resultCodes("tx_failed", "op_already_exists"))
+		return
+	}
+	s.createAccount(address, int64(amount.MustParse(FriendbotAmount)))
+	s.recordPayment(horizon.Payment{
+		Type:      "create_account",
+		To:        address,
+		AssetType: "native",
+		Amount:    amount.String(amount.MustParse(FriendbotAmount)),
+	})
+	s.ledger++
+	writeJSON(w, http.StatusOK, horizon.TransactionSuccess{Ledger: s.ledger - 1})
+}
+
+func (s *Server) serveStellarTOML(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprintf(w, "FEDERATION_SERVER = %q\n", s.URL+"/federation")
+	s.mu.Lock()
+	key := s.uriSigningKey
+	s.mu.Unlock()
+	if key != "" {
+		fmt.Fprintf(w, "URI_REQUEST_SIGNING_KEY = %q\n", key)
+	}
+}
+
+// serveFederation answers name lookups like a SEP-2 federation server
+func (s *Server) serveFederation(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("type") != "name" {
+		writeJSON(w, http.StatusNotImplemented, map[string]string{"detail": "only name lookups are supported"})
+		return
+	}
+	s.mu.Lock()
+	record, ok := s.federation[r.URL.Query().Get("q")]
+	s.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"detail": "not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, record)
+}
+
+// serveCallback accepts the envelopes posted to a SEP-7 callback
+func (s *Server) serveCallback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"detail": "callbacks must be posted"})
+		return
+	}
+	xdr := r.PostFormValue("xdr")
+	if xdr == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"detail": "missing xdr"})
+		return
+	}
+	s.mu.Lock()
+	s.callbacks = append(s.callbacks, xdr)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]string{"status": "received"})
+}
+
+func (s *Server) currentLedger() int32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ledger
+}
+
+// sortedOffers returns the offers ordered by ID, must be called with the lock held
+func (s *Server) sortedOffers() []*horizon.Offer {
+	offers := []*horizon.Offer{}
+	for _, offer := range s.offers {
+		offers = append(offers, offer)
+	}
+	sort.Slice(offers, func(i, j int) bool { return offers[i].ID < offers[j].ID })
+	return offers
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeNotFound(w http.ResponseWriter) {
+	writeProblem(w, http.StatusNotFound, "Resource Missing", "The resource at the url requested was not found.", nil)
+}
+
+func writeProblem(w http.ResponseWriter, status int, title string, detail string, extras map[string]json.RawMessage) {
+	writeJSON(w, status, newProblem(status, title, detail, extras))
+}
+
+func newProblem(status int, title string, detail string, extras map[string]json.RawMessage) *horizon.Problem {
+	typ := strings.Replace(strings.ToLower(title), " ", "_", -1)
+	return &horizon.Problem{
+		Type:   "https://stellar.org/horizon-errors/" + typ,
+		Title:  title,
+		Status: status,
+		Detail: detail,
+		Extras: extras,
+	}
+}
+
+// resultCodes builds the extras of a failed transaction the way Horizon reports them
+func resultCodes(tx string, ops ...string) map[string]json.RawMessage {
+	codes := horizon.TransactionResultCodes{TransactionCode: tx, OperationCodes: ops}
+	data, _ := json.Marshal(codes)
+	return map[string]json.RawMessage{"result_codes": data}
+}
+
+// amountString formats stroops as a decimal amount
+func amountString(stroops int64) string {
+	return amount.String(xdr.Int64(stroops))
+}
@@ -0,0 +1,489 @@
+// Package horizontest is an in-process mock of the Horizon API for offline end-to-end tests of the commands.
+//
+// A Server keeps accounts, trust lines, offers and payments in memory and serves the endpoints used by the commands:
+//
+//	GET  /accounts/{id}
+//	GET  /accounts/{id}/offers
+//	GET  /accounts/{id}/payments   (JSON, or a server-sent event stream when requested with Accept: text/event-stream)
+//	GET  /order_book
+//	POST /transactions
+//	GET  /transactions/{hash}
+//	GET  /fee_stats
+//	GET  /friendbot?addr={id}
+//
+// It also stands in for the servers of other domains: HTTPClient returns a client that sends every request to the mock
+// whatever its host, which serves a stellar.toml and a federation server for the records added with AddFederation. The
+// stellar.toml also publishes the key set with SetURIRequestSigningKey, and the envelopes posted to a SEP-7 callback
+// such as https://example.com/callback are kept for Callbacks:
+//
+//	GET  /.well-known/stellar.toml
+//	GET  /federation?type=name&q={address}
+//	POST /callback                 (form field xdr)
+//
+// Submitted envelopes are validated the way stellar-core would for the basics (source account, sequence number, fee
+// and signatures of the master keys involved) and their operations are applied to the in-memory state, so a test can
+// run a command with Run and then inspect the resulting state with Account.
+package horizontest
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/envelope"
+	"github.com/nikhilsaraf/stellar-go/federation"
+	"github.com/nikhilsaraf/stellar-go/profile"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+// FriendbotAmount is the starting balance of accounts created through the mock friendbot
+const FriendbotAmount = "10000"
+
+// BaseFee is the minimum fee per operation accepted by the mock, in stroops
+const BaseFee = 100
+
+// Server is a mock Horizon server backed by in-memory state, it is safe for concurrent use
+type Server struct {
+	*httptest.Server
+	// Passphrase is the network passphrase submitted transactions must be signed for
+	Passphrase string
+
+	mu           sync.Mutex
+	ledger       int32
+	nextID       int64
+	accounts     map[string]*account
+	offers       map[int64]*horizon.Offer
+	payments     []horizon.Payment
+	transactions []horizon.TransactionSuccess
+	subscribers  map[chan horizon.Payment]string
+	// timeouts is the number of submissions left that respond with a timeout, they are still applied if timeoutsApply
+	timeouts      int
+	timeoutsApply bool
+	// lateTimeouts is the number of submissions left that respond with a timeout and are held in late until the next
+	// submission arrives
+	lateTimeouts int
+	late         string
+	// minFee is the fee per operation a transaction needs to be accepted, raised by SurgePrice
+	minFee int64
+	// federation holds the records served by the mock federation server, keyed by federation address
+	federation map[string]federation.Record
+	// uriSigningKey is the URI_REQUEST_SIGNING_KEY served in the stellar.toml
+	uriSigningKey string
+	// callbacks are the envelopes posted to the SEP-7 callback, oldest first
+	callbacks []string
+}
+
+// account is the in-memory state of an account, amounts are in stroops
+type account struct {
+	address       string
+	sequence      int64
+	native        int64
+	trustlines    map[string]*trustline
+	inflationDest string
+	homeDomain    string
+	data          map[string]string
+}
+
+type trustline struct {
+	asset   horizon.Asset
+	balance int64
+	limit   int64
+}
+
+// NewServer starts a mock Horizon server for the network with the given passphrase, an empty passphrase uses the
+// test network. Call Close when done.
+func NewServer(passphrase string) *Server {
+	if passphrase == "" {
+		passphrase = network.TestNetworkPassphrase
+	}
+	s := &Server{
+		Passphrase:  passphrase,
+		ledger:      1,
+		nextID:      1,
+		minFee:      BaseFee,
+		accounts:    map[string]*account{},
+		offers:      map[int64]*horizon.Offer{},
+		subscribers: map[chan horizon.Payment]string{},
+		federation:  map[string]federation.Record{},
+	}
+	s.Server = httptest.NewServer(s.handler())
+	return s
+}
+
+// Profile returns a network profile that points at the server
+func (s *Server) Profile() *profile.Profile {
+	return &profile.Profile{
+		Name:         "horizontest",
+		HorizonURL:   s.URL,
+		Passphrase:   s.Passphrase,
+		FriendbotURL: s.URL + "/friendbot",
+		BaseFee:      BaseFee,
+	}
+}
+
+// CreateAccount adds a funded account with the given native balance, e.g. "100.5"
+func (s *Server) CreateAccount(address string, balance string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.createAccount(address, int64(amount.MustParse(balance)))
+}
+
+// AddTrustline adds a trust line with the given balance to an existing account, an empty limit means the maximum
+func (s *Server) AddTrustline(address string, code string, issuer string, balance string, limit string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acct, ok := s.accounts[address]
+	if !ok {
+		return fmt.Errorf("account %s does not exist", address)
+	}
+	l := int64(maxLimit)
+	if limit != "" {
+		l = int64(amount.MustParse(limit))
+	}
+	asset := creditAsset(code, issuer)
+	acct.trustlines[assetKey(asset)] = &trustline{asset: asset, balance: int64(amount.MustParse(balance)), limit: l}
+	return nil
+}
+
+// AddOffer adds an open offer, its ID is assigned by the server and returned
+func (s *Server) AddOffer(offer horizon.Offer) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	offer.ID = s.id()
+	offer.PT = fmt.Sprintf("%d", offer.ID)
+	s.offers[offer.ID] = &offer
+	return offer.ID
+}
+
+// AddPayment records a payment as if it had happened on the network and streams it to the listeners of its accounts
+func (s *Server) AddPayment(payment horizon.Payment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recordPayment(payment)
+}
+
+// Account returns the current state of an account as Horizon would report it
+func (s *Server) Account(address string) (horizon.Account, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acct, ok := s.accounts[address]
+	if !ok {
+		return horizon.Account{}, false
+	}
+	return acct.resource(), true
+}
+
+// TimeOut makes the next n submissions respond with 504 Timeout the way Horizon does when a transaction does not make
+// it into a ledger in time. When applied is set they are still validated and applied, like a transaction that was
+// included after Horizon gave up waiting for it.
+func (s *Server) TimeOut(n int, applied bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timeouts = n
+	s.timeoutsApply = applied
+}
+
+// TimeOutLate makes the next n submissions respond with 504 Timeout and only be applied when the following submission
+// arrives, like a transaction that is included after the client has already looked for it and not found it
+func (s *Server) TimeOutLate(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lateTimeouts = n
+}
+
+// SurgePrice sets the fee per operation a transaction needs to be accepted, as during surge pricing, it is reported as
+// every percentile by /fee_stats
+func (s *Server) SurgePrice(stroops int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.minFee = stroops
+}
+
+// AddFederation makes the mock federation server resolve record.StellarAddress, for any domain, to the record
+func (s *Server) AddFederation(record federation.Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.federation[record.StellarAddress] = record
+}
+
+// SetURIRequestSigningKey makes the stellar.toml of every domain publish address as its URI_REQUEST_SIGNING_KEY, so that
+// SEP-7 requests signed with its seed are verified
+func (s *Server) SetURIRequestSigningKey(address string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uriSigningKey = address
+}
+
+// HTTPClient returns a client that sends every request to the server, whatever the scheme and host of its URL
+func (s *Server) HTTPClient() *http.Client {
+	target, _ := url.Parse(s.URL)
+	return &http.Client{Transport: &redirectTransport{target: target}}
+}
+
+// redirectTransport rewrites the scheme and host of every request to those of the target
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t *redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the request, WithContext returns a shallow copy
+	r = r.WithContext(r.Context())
+	u := *r.URL
+	u.Scheme = t.target.Scheme
+	u.Host = t.target.Host
+	r.URL = &u
+	r.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// BumpSequence increments the sequence number of an account as if another transaction from it had been included
+func (s *Server) BumpSequence(address string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acct, ok := s.accounts[address]
+	if !ok {
+		return fmt.Errorf("account %s does not exist", address)
+	}
+	acct.sequence++
+	return nil
+}
+
+// takeTimeout consumes one pending timeout if there is one for submissions that are, or are not, applied
+func (s *Server) takeTimeout(applied bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timeouts == 0 || s.timeoutsApply != applied {
+		return false
+	}
+	s.timeouts--
+	return true
+}
+
+// Sequence returns the current sequence number of an account, 0 when it does not exist
+func (s *Server) Sequence(address string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acct, ok := s.accounts[address]
+	if !ok {
+		return 0
+	}
+	return acct.sequence
+}
+
+// holdLate keeps the envelope to be applied with the next submission if a late timeout is pending, it returns the
+// envelope held so far, which lands now
+func (s *Server) holdLate(txeBase64 string) (held bool, landing string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	landing, s.late = s.late, ""
+	if s.lateTimeouts > 0 {
+		s.lateTimeouts--
+		s.late = txeBase64
+		return true, landing
+	}
+	return false, landing
+}
+
+// Transactions returns the transactions accepted by the server so far, oldest first
+func (s *Server) Transactions() []horizon.TransactionSuccess {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]horizon.TransactionSuccess{}, s.transactions...)
+}
+
+// Callbacks returns the envelopes posted to the mock SEP-7 callback so far, oldest first
+func (s *Server) Callbacks() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.callbacks...)
+}
+
+// Describe decodes a base64-encoded envelope, such as one from Transactions or Callbacks, for a test to check what
+// was signed
+func Describe(txeBase64 string) (*envelope.Description, error) {
+	var env xdr.TransactionEnvelope
+	e := xdr.SafeUnmarshalBase64(txeBase64, &env)
+	if e != nil {
+		return nil, e
+	}
+	return envelope.Describe(&env)
+}
+
+// CheckPayment fails the test unless the envelope is a single payment of amount lumens from source to dest, using the
+// sequence number after sequence and signed by source alone for the server's network. It returns the description for
+// any further checks.
+func (s *Server) CheckPayment(t testing.TB, txeBase64 string, source string, sequence int64, dest string, amount string) *envelope.Description {
+	d, e := Describe(txeBase64)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if d.Source != source {
+		t.Errorf("source %s, want %s", d.Source, source)
+	}
+	if d.Sequence != sequence+1 {
+		t.Errorf("sequence %d, want %d", d.Sequence, sequence+1)
+	}
+	if len(d.Operations) != 1 || d.Operations[0].Type != "payment" {
+		t.Fatalf("operations %+v, want a single payment", d.Operations)
+	}
+	want := []string{dest, "native", amount}
+	for i, f := range d.Operations[0].Fields {
+		if i < len(want) && f.Value != want[i] {
+			t.Errorf("%s is %s, want %s", f.Name, f.Value, want[i])
+		}
+	}
+	networkName := ""
+	for _, h := range d.Hashes {
+		if h.Passphrase == s.Passphrase {
+			networkName = h.Network
+		}
+	}
+	if len(d.Signatures) != 1 || d.Signatures[0].SignedBy != source || d.Signatures[0].Network != networkName {
+		t.Errorf("signatures %+v, want one by %s for the %s network", d.Signatures, source, networkName)
+	}
+	return d
+}
+
+// Result is the outcome of running a command
+type Result struct {
+	Stdout string
+	Stderr string
+	Code   int
+}
+
+// Run executes the command tree rooted at root against the server, feeding stdin to the command. The server is
+// selected by setting the profile environment variables for the duration of the call, so tests using Run must not
+// run in parallel.
+func (s *Server) Run(root *cli.Command, stdin string, args ...string) Result {
+	p := s.Profile()
+	restore := setenv(map[string]string{
+		profile.EnvConfig:       os.DevNull,
+		profile.EnvNetwork:      "",
+		profile.EnvHorizonURL:   p.HorizonURL,
+		profile.EnvPassphrase:   p.Passphrase,
+		profile.EnvFriendbotURL: p.FriendbotURL,
+		profile.EnvBaseFee:      fmt.Sprintf("%d", p.BaseFee),
+	})
+	defer restore()
+
+	var stdout, stderr bytes.Buffer
+	ctx := &cli.Context{
+		Stdin:  strings.NewReader(stdin),
+		Stdout: &stdout,
+		Stderr: &stderr,
+		HTTP:   s.HTTPClient(),
+	}
+	code := ctx.Execute(root, args)
+	return Result{Stdout: stdout.String(), Stderr: stderr.String(), Code: code}
+}
+
+// setenv sets the variables, unsetting those with empty values, and returns a func that restores the previous values
+func setenv(vars map[string]string) func() {
+	previous := map[string]*string{}
+	for name, value := range vars {
+		if old, ok := os.LookupEnv(name); ok {
+			previous[name] = &old
+		} else {
+			previous[name] = nil
+		}
+		if value == "" {
+			os.Unsetenv(name)
+		} else {
+			os.Setenv(name, value)
+		}
+	}
+	return func() {
+		for name, old := range previous {
+			if old == nil {
+				os.Unsetenv(name)
+			} else {
+				os.Setenv(name, *old)
+			}
+		}
+	}
+}
+
+// createAccount must be called with the lock held
+func (s *Server) createAccount(address string, balance int64) *account {
+	acct := s.newAccount(address, balance)
+	s.accounts[address] = acct
+	return acct
+}
+
+// id returns a new unique ID for offers and paging tokens, must be called with the lock held
+func (s *Server) id() int64 {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+// recordPayment must be called with the lock held
+func (s *Server) recordPayment(payment horizon.Payment) {
+	if payment.ID == "" {
+		payment.ID = fmt.Sprintf("%d", s.id())
+	}
+	if payment.PagingToken == "" {
+		payment.PagingToken = payment.ID
+	}
+	s.payments = append(s.payments, payment)
+	for ch, address := range s.subscribers {
+		if address == payment.From || address == payment.To {
+			select {
+			case ch <- payment:
+			default:
+				// a listener that does not keep up misses payments instead of blocking the server
+			}
+		}
+	}
+}
+
+// resource converts the account to the Horizon representation
+func (acct *account) resource() horizon.Account {
+	r := horizon.Account{
+		ID:                   acct.address,
+		PT:                   acct.address,
+		AccountID:            acct.address,
+		Sequence:             fmt.Sprintf("%d", acct.sequence),
+		SubentryCount:        int32(len(acct.trustlines) + len(acct.data)),
+		InflationDestination: acct.inflationDest,
+		HomeDomain:           acct.homeDomain,
+		Thresholds:           horizon.AccountThresholds{},
+		Signers: []horizon.Signer{
+			{PublicKey: acct.address, Weight: 1, Key: acct.address, Type: "ed25519_public_key"},
+		},
+		Data: map[string]string{},
+	}
+	for k, v := range acct.data {
+		r.Data[k] = v
+	}
+
+	keys := []string{}
+	for k := range acct.trustlines {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		tl := acct.trustlines[k]
+		r.Balances = append(r.Balances, horizon.Balance{
+			Balance: amountString(tl.balance),
+			Limit:   amountString(tl.limit),
+			Asset:   tl.asset,
+		})
+	}
+	r.Balances = append(r.Balances, horizon.Balance{
+		Balance: amountString(acct.native),
+		Asset:   horizon.Asset{Type: "native"},
+	})
+	return r
+}
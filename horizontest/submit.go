@@ -0,0 +1,450 @@
+package horizontest
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// maxLimit is the limit of a trust line created without one
+const maxLimit = math.MaxInt64
+
+// opError is the result code of a failed operation
+type opError string
+
+// ledgerState is the part of the state that operations change
+type ledgerState struct {
+	accounts map[string]*account
+	offers   map[int64]*horizon.Offer
+}
+
+// submit validates the envelope and applies it to the state, either the success response or the problem is returned
+func (s *Server) submit(txeBase64 string) (*horizon.TransactionSuccess, *horizon.Problem) {
+	if s.takeTimeout(false) {
+		return nil, newProblem(http.StatusGatewayTimeout, "Timeout", "Your request timed out before completing.", nil)
+	}
+	var env xdr.TransactionEnvelope
+	e := xdr.SafeUnmarshalBase64(txeBase64, &env)
+	if e != nil {
+		return nil, newProblem(http.StatusBadRequest, "Transaction Malformed", "could not decode the transaction envelope: "+e.Error(), nil)
+	}
+	hash, e := network.HashTransaction(&env.Tx, s.Passphrase)
+	if e != nil {
+		return nil, newProblem(http.StatusBadRequest, "Transaction Malformed", e.Error(), nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, ok := s.accounts[env.Tx.SourceAccount.Address()]
+	if !ok {
+		return nil, txFailed("tx_no_source_account")
+	}
+	if int64(env.Tx.SeqNum) != source.sequence+1 {
+		return nil, txFailed("tx_bad_seq")
+	}
+	if len(env.Tx.Operations) == 0 {
+		return nil, txFailed("tx_missing_operation")
+	}
+	if int64(env.Tx.Fee) < s.minFee*int64(len(env.Tx.Operations)) {
+		return nil, txFailed("tx_insufficient_fee")
+	}
+	if int64(env.Tx.Fee) > source.native {
+		return nil, txFailed("tx_insufficient_balance")
+	}
+	if tb := env.Tx.TimeBounds; tb != nil {
+		now := uint64(time.Now().Unix())
+		if now < uint64(tb.MinTime) {
+			return nil, txFailed("tx_too_early")
+		}
+		if tb.MaxTime != 0 && now > uint64(tb.MaxTime) {
+			return nil, txFailed("tx_too_late")
+		}
+	}
+
+	// every account used as a source must have signed with its master key
+	signers := map[string]bool{source.address: true}
+	for _, op := range env.Tx.Operations {
+		if op.SourceAccount != nil {
+			signers[op.SourceAccount.Address()] = true
+		}
+	}
+	for address := range signers {
+		if !signed(address, hash[:], env.Signatures) {
+			return nil, txFailed("tx_bad_auth")
+		}
+	}
+
+	// the fee and the sequence number are consumed even if an operation fails
+	source.sequence++
+	source.native -= int64(env.Tx.Fee)
+
+	// operations are applied to a copy of the state so a failed transaction leaves no trace
+	staged := s.snapshot()
+	payments := []horizon.Payment{}
+	codes := make([]string, len(env.Tx.Operations))
+	failed := false
+	for i, op := range env.Tx.Operations {
+		opSource := source.address
+		if op.SourceAccount != nil {
+			opSource = op.SourceAccount.Address()
+		}
+		payment, opErr := s.apply(&staged, opSource, op.Body)
+		if opErr != "" {
+			failed = true
+			codes[i] = string(opErr)
+			continue
+		}
+		codes[i] = "op_success"
+		if payment != nil {
+			payment.TransactionHash = hex.EncodeToString(hash[:])
+			payments = append(payments, *payment)
+		}
+	}
+	if failed {
+		return nil, newProblem(http.StatusBadRequest, "Transaction Failed",
+			"The transaction failed when submitted to the stellar network.", resultCodes("tx_failed", codes...))
+	}
+
+	s.accounts = staged.accounts
+	s.offers = staged.offers
+	for _, payment := range payments {
+		s.recordPayment(payment)
+	}
+	success := horizon.TransactionSuccess{
+		Hash:   hex.EncodeToString(hash[:]),
+		Ledger: s.ledger,
+		Env:    txeBase64,
+		Result: successResult(int64(env.Tx.Fee), len(env.Tx.Operations)),
+	}
+	s.ledger++
+	s.transactions = append(s.transactions, success)
+	return &success, nil
+}
+
+// apply applies a single operation to the staged state, returning the payment it made if any or the result code when
+// it fails
+func (s *Server) apply(staged *ledgerState, sourceAddress string, body xdr.OperationBody) (*horizon.Payment, opError) {
+	accounts := staged.accounts
+	source, ok := accounts[sourceAddress]
+	if !ok {
+		return nil, "op_no_source_account"
+	}
+
+	switch body.Type {
+	case xdr.OperationTypeCreateAccount:
+		op := body.CreateAccountOp
+		dest := op.Destination.Address()
+		if _, ok := accounts[dest]; ok {
+			return nil, "op_already_exists"
+		}
+		if int64(op.StartingBalance) > source.native {
+			return nil, "op_underfunded"
+		}
+		source.native -= int64(op.StartingBalance)
+		created := s.newAccount(dest, int64(op.StartingBalance))
+		accounts[dest] = created
+		return &horizon.Payment{
+			Type: "create_account", SourceAccount: sourceAddress, From: sourceAddress, To: dest,
+			AssetType: "native", Amount: amountString(int64(op.StartingBalance)),
+		}, ""
+
+	case xdr.OperationTypePayment:
+		op := body.PaymentOp
+		dest, ok := accounts[op.Destination.Address()]
+		if !ok {
+			return nil, "op_no_destination"
+		}
+		asset, e := horizonAsset(op.Asset)
+		if e != nil {
+			return nil, "op_malformed"
+		}
+		opErr := transfer(source, dest, asset, int64(op.Amount))
+		if opErr != "" {
+			return nil, opErr
+		}
+		return &horizon.Payment{
+			Type: "payment", SourceAccount: sourceAddress, From: sourceAddress, To: dest.address,
+			AssetType: asset.Type, AssetCode: asset.Code, AssetIssuer: asset.Issuer, Amount: amountString(int64(op.Amount)),
+		}, ""
+
+	case xdr.OperationTypeChangeTrust:
+		op := body.ChangeTrustOp
+		asset, e := horizonAsset(op.Line)
+		if e != nil || asset.Type == "native" {
+			return nil, "op_malformed"
+		}
+		if _, ok := accounts[asset.Issuer]; !ok {
+			return nil, "op_no_issuer"
+		}
+		key := assetKey(asset)
+		tl, ok := source.trustlines[key]
+		if op.Limit == 0 {
+			if ok && tl.balance > 0 {
+				return nil, "op_invalid_limit"
+			}
+			delete(source.trustlines, key)
+			return nil, ""
+		}
+		if !ok {
+			tl = &trustline{asset: asset}
+			source.trustlines[key] = tl
+		}
+		if int64(op.Limit) < tl.balance {
+			return nil, "op_invalid_limit"
+		}
+		tl.limit = int64(op.Limit)
+		return nil, ""
+
+	case xdr.OperationTypeManageOffer:
+		op := body.ManageOfferOp
+		return nil, s.manageOffer(staged.offers, sourceAddress, op.Selling, op.Buying, int64(op.Amount), op.Price, int64(op.OfferId))
+
+	case xdr.OperationTypeCreatePassiveOffer:
+		op := body.CreatePassiveOfferOp
+		return nil, s.manageOffer(staged.offers, sourceAddress, op.Selling, op.Buying, int64(op.Amount), op.Price, 0)
+
+	case xdr.OperationTypeSetOptions:
+		op := body.SetOptionsOp
+		if op.InflationDest != nil {
+			dest := op.InflationDest.Address()
+			if _, ok := accounts[dest]; !ok {
+				return nil, "op_invalid_inflation"
+			}
+			source.inflationDest = dest
+		}
+		if op.HomeDomain != nil {
+			source.homeDomain = string(*op.HomeDomain)
+		}
+		return nil, ""
+
+	case xdr.OperationTypeAccountMerge:
+		dest, ok := accounts[body.Destination.Address()]
+		if !ok {
+			return nil, "op_no_account"
+		}
+		if dest.address == source.address {
+			return nil, "op_malformed"
+		}
+		if len(source.trustlines) > 0 || len(source.data) > 0 {
+			return nil, "op_has_sub_entries"
+		}
+		dest.native += source.native
+		delete(accounts, source.address)
+		return &horizon.Payment{
+			Type: "account_merge", SourceAccount: sourceAddress, From: sourceAddress, To: dest.address,
+			AssetType: "native", Amount: amountString(source.native),
+		}, ""
+
+	case xdr.OperationTypeManageData:
+		op := body.ManageDataOp
+		if op.DataValue == nil {
+			delete(source.data, string(op.DataName))
+		} else {
+			source.data[string(op.DataName)] = string(*op.DataValue)
+		}
+		return nil, ""
+
+	case xdr.OperationTypeInflation:
+		// inflation never runs on the mock, it is accepted and does nothing
+		return nil, ""
+
+	case xdr.OperationTypeBumpSequence:
+		op := body.BumpSequenceOp
+		if int64(op.BumpTo) > source.sequence {
+			source.sequence = int64(op.BumpTo)
+		}
+		return nil, ""
+	}
+	return nil, "op_not_supported"
+}
+
+// transfer moves an amount of an asset between two accounts, the issuer of an asset can send and receive any amount
+func transfer(from *account, to *account, asset horizon.Asset, stroops int64) opError {
+	if stroops <= 0 {
+		return "op_malformed"
+	}
+	if asset.Type == "native" {
+		if stroops > from.native {
+			return "op_underfunded"
+		}
+		from.native -= stroops
+		to.native += stroops
+		return ""
+	}
+
+	key := assetKey(asset)
+	if from.address != asset.Issuer {
+		tl, ok := from.trustlines[key]
+		if !ok {
+			return "op_src_no_trust"
+		}
+		if stroops > tl.balance {
+			return "op_underfunded"
+		}
+		tl.balance -= stroops
+	}
+	if to.address != asset.Issuer {
+		tl, ok := to.trustlines[key]
+		if !ok {
+			return "op_no_trust"
+		}
+		if tl.balance+stroops > tl.limit {
+			return "op_line_full"
+		}
+		tl.balance += stroops
+	}
+	return ""
+}
+
+// manageOffer creates, updates or deletes an offer, offers are never matched against each other by the mock
+func (s *Server) manageOffer(offers map[int64]*horizon.Offer, seller string, sellingXDR xdr.Asset, buyingXDR xdr.Asset, stroops int64, price xdr.Price, offerID int64) opError {
+	selling, e := horizonAsset(sellingXDR)
+	if e != nil {
+		return "op_malformed"
+	}
+	buying, e := horizonAsset(buyingXDR)
+	if e != nil || price.N <= 0 || price.D <= 0 {
+		return "op_malformed"
+	}
+
+	if offerID != 0 {
+		offer, ok := offers[offerID]
+		if !ok || offer.Seller != seller {
+			return "op_not_found"
+		}
+		if stroops == 0 {
+			delete(offers, offerID)
+			return ""
+		}
+	} else if stroops == 0 {
+		return "op_malformed"
+	} else {
+		offerID = s.id()
+	}
+
+	offers[offerID] = &horizon.Offer{
+		ID:      offerID,
+		PT:      fmt.Sprintf("%d", offerID),
+		Seller:  seller,
+		Selling: selling,
+		Buying:  buying,
+		Amount:  amountString(stroops),
+		PriceR:  horizon.Price{N: int32(price.N), D: int32(price.D)},
+		Price:   fmt.Sprintf("%.7f", float64(price.N)/float64(price.D)),
+	}
+	return ""
+}
+
+// snapshot deep copies the state so operations can be applied atomically, must be called with the lock held
+func (s *Server) snapshot() ledgerState {
+	offers := map[int64]*horizon.Offer{}
+	for id, offer := range s.offers {
+		o := *offer
+		offers[id] = &o
+	}
+	accounts := map[string]*account{}
+	for address, acct := range s.accounts {
+		c := *acct
+		c.trustlines = map[string]*trustline{}
+		for k, tl := range acct.trustlines {
+			t := *tl
+			c.trustlines[k] = &t
+		}
+		c.data = map[string]string{}
+		for k, v := range acct.data {
+			c.data[k] = v
+		}
+		accounts[address] = &c
+	}
+	return ledgerState{accounts: accounts, offers: offers}
+}
+
+// newAccount builds an account without adding it to the state, must be called with the lock held
+func (s *Server) newAccount(address string, balance int64) *account {
+	return &account{
+		// like stellar-core, new accounts start with the current ledger number in the high 32 bits
+		address:    address,
+		sequence:   int64(s.ledger) << 32,
+		native:     balance,
+		trustlines: map[string]*trustline{},
+		data:       map[string]string{},
+	}
+}
+
+// signed checks that one of the signatures is a valid signature of the hash by the address' master key
+func signed(address string, hash []byte, signatures []xdr.DecoratedSignature) bool {
+	kp, e := keypair.Parse(address)
+	if e != nil {
+		return false
+	}
+	hint := kp.Hint()
+	for _, sig := range signatures {
+		if sig.Hint != xdr.SignatureHint(hint) {
+			continue
+		}
+		if kp.Verify(hash, sig.Signature) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func txFailed(code string) *horizon.Problem {
+	return newProblem(http.StatusBadRequest, "Transaction Failed",
+		"The transaction failed when submitted to the stellar network.", resultCodes(code))
+}
+
+// successResult encodes the result XDR of a successful transaction
+func successResult(fee int64, ops int) string {
+	results := make([]xdr.OperationResult, ops)
+	result := xdr.TransactionResult{
+		FeeCharged: xdr.Int64(fee),
+		Result: xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxSuccess,
+			Results: &results,
+		},
+	}
+	encoded, e := xdr.MarshalBase64(result)
+	if e != nil {
+		return ""
+	}
+	return encoded
+}
+
+// horizonAsset converts an XDR asset to its Horizon representation
+func horizonAsset(asset xdr.Asset) (horizon.Asset, error) {
+	var typ, code, issuer string
+	e := asset.Extract(&typ, &code, &issuer)
+	if e != nil {
+		return horizon.Asset{}, e
+	}
+	return horizon.Asset{Type: typ, Code: code, Issuer: issuer}, nil
+}
+
+func creditAsset(code string, issuer string) horizon.Asset {
+	if len(code) <= 4 {
+		return horizon.Asset{Type: "credit_alphanum4", Code: code, Issuer: issuer}
+	}
+	return horizon.Asset{Type: "credit_alphanum12", Code: code, Issuer: issuer}
+}
+
+// assetKey identifies an asset independently of how its type is spelled
+func assetKey(asset horizon.Asset) string {
+	if asset.Type == "native" {
+		return "native"
+	}
+	return asset.Code + ":" + asset.Issuer
+}
+
+func parseAddress(address string) ([]byte, error) {
+	return strkey.Decode(strkey.VersionByteAccountID, address)
+}
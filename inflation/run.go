@@ -0,0 +1,59 @@
+// Package inflation implements the commands that deal with the network's inflation operation.
+package inflation
+
+import (
+	"fmt"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/secret"
+	"github.com/nikhilsaraf/stellar-go/submit"
+	b "github.com/stellar/go/build"
+)
+
+// RunCmd submits an inflation operation
+var RunCmd = &cli.Command{
+	Name:    "run",
+	Summary: "submit an inflation operation",
+	Usage:   "[-secret <source>] [-dry-run | -unsigned -sequence <n>] [-out <file>]",
+	Run:     runInflation,
+}
+
+func runInflation(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	secretPtr := secret.Flag(fs, "secret", "source account's secret key")
+	opts := submit.Flags(fs)
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	e = opts.Validate()
+	if e != nil {
+		return e
+	}
+
+	p, e := ctx.Profile()
+	if e != nil {
+		return e
+	}
+	fmt.Fprintln(ctx.Info(), "network:", p)
+
+	sourceAddress, sourceKP, e := opts.Signer(*secretPtr, ctx)
+	if e != nil {
+		return e
+	}
+	fmt.Fprintln(ctx.Info(), "source account", sourceAddress+", running inflation now.")
+
+	horizonClient := p.Client()
+	txn, e := opts.Build(p, horizonClient, sourceAddress,
+		b.Inflation(),
+	)
+	if e != nil {
+		return e
+	}
+	env, e := txn.Sign()
+	if e != nil {
+		return e
+	}
+	_, e = opts.Finish(ctx, &env, sourceKP, horizonClient)
+	return e
+}
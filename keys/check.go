@@ -0,0 +1,87 @@
+package keys
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/keystore"
+	"github.com/nikhilsaraf/stellar-go/secret"
+)
+
+// CheckCmd reads a secret key and prints the address it belongs to
+var CheckCmd = &cli.Command{
+	Name:    "check",
+	Summary: "read a secret key and print its address, or verify the keys in the keystore",
+	Usage:   "[-secret <source> | -all]",
+	Run:     runCheck,
+}
+
+func runCheck(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	secretPtr := secret.Flag(fs, "secret", "secret key to check")
+	allPtr := fs.Bool("all", false, "(optional) unlock every key in the keystore and verify it matches its stored address")
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	if *allPtr {
+		return checkAll(ctx)
+	}
+
+	sourceKP, e := secret.LoadKeypair(*secretPtr, ctx)
+	if e != nil {
+		return e
+	}
+	return ctx.Emit(Key{Address: sourceKP.Address()}, func(w io.Writer) {
+		fmt.Fprintln(w, "address:", sourceKP.Address())
+	})
+}
+
+// checkAll unlocks every keystore entry with a single passphrase, entries that fail are reported and counted
+func checkAll(ctx *cli.Context) error {
+	store, e := keystore.Open(keystore.DefaultPath())
+	if e != nil {
+		return e
+	}
+	aliases := store.Aliases()
+	if len(aliases) == 0 {
+		return fmt.Errorf("keystore %s has no keys", store.Path())
+	}
+
+	passphrase, e := secret.Passphrase(ctx, "Enter keystore passphrase: ")
+	if e != nil {
+		return e
+	}
+	failed := 0
+	keys := []StoredKey{}
+	for _, alias := range aliases {
+		ok := true
+		key := StoredKey{Alias: alias, OK: &ok}
+		kp, e := store.Unlock(alias, []byte(passphrase))
+		if e != nil {
+			failed++
+			ok = false
+			key.Error = e.Error()
+		} else {
+			key.Address = kp.Address()
+		}
+		keys = append(keys, key)
+	}
+	e = ctx.Emit(keyList{Keys: keys}, func(w io.Writer) {
+		for _, key := range keys {
+			if key.Error != "" {
+				fmt.Fprintf(w, "%s: FAILED (%s)\n", key.Alias, key.Error)
+			} else {
+				fmt.Fprintf(w, "%s: ok %s\n", key.Alias, key.Address)
+			}
+		}
+	})
+	if e != nil {
+		return e
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d keys could not be unlocked", failed, len(aliases))
+	}
+	return nil
+}
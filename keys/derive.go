@@ -0,0 +1,89 @@
+package keys
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/mnemonic"
+	"github.com/nikhilsaraf/stellar-go/secret"
+)
+
+// DeriveCmd lists the accounts derived from a SEP-5 mnemonic phrase
+var DeriveCmd = &cli.Command{
+	Name:    "derive",
+	Summary: "list the accounts derived from a SEP-5 mnemonic phrase",
+	Usage:   "[-mnemonic <source>] [-passphrase <source>] [-start <index>] [-n <count>] [-seeds] [-save <alias>]",
+	Run:     runDerive,
+}
+
+func runDerive(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	mnemonicPtr := fs.String("mnemonic", secret.DefaultSpec, "source of the mnemonic phrase: prompt, stdin, file:<path>, env:<VAR> or cmd:<command>")
+	passphrasePtr := fs.String("passphrase", "", "(optional) source of the BIP-39 passphrase for the mnemonic phrase, e.g. prompt or env:<VAR>")
+	startPtr := fs.Uint("start", 0, "(optional) index of the first account to derive")
+	countPtr := fs.Uint("n", 10, "(optional) number of accounts to derive")
+	seedsPtr := fs.Bool("seeds", false, "(optional) also print the secret seed of each account")
+	savePtr := fs.String("save", "", "(optional) alias to save the account at -start under in the encrypted keystore, only one account is derived")
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	if *countPtr == 0 {
+		return cli.UsageErrorf("the -n flag must be at least 1")
+	}
+	if *startPtr >= mnemonic.IndexLimit {
+		return cli.UsageErrorf("the -start index must be below %d", uint(mnemonic.IndexLimit))
+	}
+	if *savePtr == "" && (*countPtr > mnemonic.IndexLimit || *startPtr+*countPtr > mnemonic.IndexLimit) {
+		return cli.UsageErrorf("-start + -n must not exceed %d, accounts past index %d cannot be derived", uint(mnemonic.IndexLimit), uint(mnemonic.IndexLimit-1))
+	}
+
+	words, e := secret.Read(*mnemonicPtr, "mnemonic phrase", ctx)
+	if e != nil {
+		return e
+	}
+	seed, e := mnemonicSeed(ctx, words, *passphrasePtr)
+	if e != nil {
+		return e
+	}
+
+	start := uint32(*startPtr)
+	if *savePtr != "" {
+		pair, e := mnemonic.Account(seed, start)
+		if e != nil {
+			return e
+		}
+		e = save(ctx, *savePtr, pair)
+		if e != nil {
+			return e
+		}
+		key := Key{Address: pair.Address(), Alias: *savePtr, Path: mnemonic.Path(start)}
+		return ctx.Emit(key, func(w io.Writer) {
+			fmt.Fprintf(w, "%-16s %s\n", key.Path, key.Address)
+		})
+	}
+
+	keys := []Key{}
+	for n := uint32(0); n < uint32(*countPtr); n++ {
+		i := start + n
+		pair, e := mnemonic.Account(seed, i)
+		if e != nil {
+			return e
+		}
+		key := Key{Address: pair.Address(), Path: mnemonic.Path(i)}
+		if *seedsPtr {
+			key.Seed = pair.Seed()
+		}
+		keys = append(keys, key)
+	}
+	return ctx.Emit(keyList{Keys: keys}, func(w io.Writer) {
+		for _, key := range keys {
+			if key.Seed != "" {
+				fmt.Fprintf(w, "%-16s %s %s\n", key.Path, key.Address, key.Seed)
+			} else {
+				fmt.Fprintf(w, "%-16s %s\n", key.Path, key.Address)
+			}
+		}
+	})
+}
@@ -0,0 +1,201 @@
+// Package keys implements the commands that generate, check and store key pairs.
+package keys
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/keystore"
+	"github.com/nikhilsaraf/stellar-go/mnemonic"
+	"github.com/nikhilsaraf/stellar-go/secret"
+	"github.com/nikhilsaraf/stellar-go/vanity"
+	"github.com/stellar/go/keypair"
+)
+
+// GenCmd generates a new random key pair
+var GenCmd = &cli.Command{
+	Name:    "gen",
+	Summary: "generate a new random key pair, optionally from a new SEP-5 mnemonic phrase or matching a vanity pattern",
+	Usage:   "[-mnemonic [-words <n>] [-passphrase <source>] | [-prefix <chars>] [-suffix <chars>] [-match <regex>] [-workers <n>]] [-save <alias>]",
+	Run:     runGen,
+}
+
+func runGen(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	savePtr := fs.String("save", "", "(optional) alias to save the key pair under in the encrypted keystore instead of printing the seed")
+	mnemonicPtr := fs.Bool("mnemonic", false, "(optional) generate a mnemonic phrase and derive the key pair of its first account (m/44'/148'/0')")
+	wordsPtr := fs.Int("words", 24, "(optional) number of words in the mnemonic phrase: 12, 15, 18, 21 or 24")
+	passphrasePtr := fs.String("passphrase", "", "(optional) source of the BIP-39 passphrase for the mnemonic phrase, e.g. prompt or env:<VAR>")
+	prefixPtr := fs.String("prefix", "", "(optional) search for an address starting with these characters, e.g. GABC")
+	suffixPtr := fs.String("suffix", "", "(optional) search for an address ending with these characters")
+	matchPtr := fs.String("match", "", "(optional) search for an address matching this regular expression")
+	workersPtr := fs.Int("workers", runtime.NumCPU(), "(optional) number of parallel workers for the vanity search")
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	isVanity := *prefixPtr != "" || *suffixPtr != "" || *matchPtr != ""
+	if isVanity && *mnemonicPtr {
+		return cli.UsageErrorf("a vanity search cannot be combined with -mnemonic")
+	}
+
+	var pair *keypair.Full
+	var words string
+	if isVanity {
+		m, e := vanity.Compile(*prefixPtr, *suffixPtr, *matchPtr)
+		if e != nil {
+			return cli.UsageErrorf("%s", e)
+		}
+		pair, e = searchVanity(ctx, m, *workersPtr)
+		if e != nil {
+			return e
+		}
+	} else if *mnemonicPtr {
+		words, e = mnemonic.Generate(*wordsPtr)
+		if e != nil {
+			return cli.UsageErrorf("%s", e)
+		}
+		pair, e = deriveAccount(ctx, words, *passphrasePtr, 0)
+		if e != nil {
+			return e
+		}
+	} else {
+		pair, e = keypair.Random()
+		if e != nil {
+			return e
+		}
+	}
+
+	key := Key{Address: pair.Address(), Alias: *savePtr}
+	if *savePtr == "" {
+		key.Seed = pair.Seed()
+	} else {
+		e = save(ctx, *savePtr, pair)
+		if e != nil {
+			return e
+		}
+	}
+	if words != "" {
+		// the words are the backup, they are printed even when the derived key is saved to the keystore
+		key.Mnemonic = words
+		key.Path = mnemonic.Path(0)
+	}
+	return ctx.Emit(key, func(w io.Writer) {
+		if key.Mnemonic != "" {
+			fmt.Fprintln(w, "Mnemonic:", key.Mnemonic)
+			fmt.Fprintln(w, "Path:    ", key.Path)
+		}
+		if key.Seed != "" {
+			fmt.Fprintln(w, "Seed:   ", key.Seed)
+		}
+		fmt.Fprintln(w, "Address:", key.Address)
+	})
+}
+
+// searchVanity runs the vanity search until it finds a match or is interrupted, reporting progress on stderr
+func searchVanity(ctx *cli.Context, m *vanity.Matcher, workers int) (*keypair.Full, error) {
+	expected := m.ExpectedAttempts()
+	if expected > 0 {
+		fmt.Fprintf(ctx.Stderr, "searching with %d workers, expecting about %.0f attempts, press Ctrl-C to stop\n", workers, expected)
+	} else {
+		fmt.Fprintf(ctx.Stderr, "searching with %d workers, press Ctrl-C to stop\n", workers)
+	}
+
+	var attempts uint64
+	start := time.Now()
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	defer close(finished)
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	// stop the search cleanly on Ctrl-C and report progress until it finishes
+	go func() {
+		ticker := time.NewTicker(2 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-finished:
+				return
+			case <-interrupt:
+				close(done)
+				return
+			case <-ticker.C:
+				reportProgress(ctx, atomic.LoadUint64(&attempts), time.Since(start), expected)
+			}
+		}
+	}()
+
+	pair, e := vanity.Search(m, workers, done, &attempts)
+	reportProgress(ctx, atomic.LoadUint64(&attempts), time.Since(start), 0)
+	return pair, e
+}
+
+// reportProgress prints the attempt rate and, when the difficulty is known, the expected time left
+func reportProgress(ctx *cli.Context, attempts uint64, elapsed time.Duration, expected float64) {
+	rate := float64(attempts) / elapsed.Seconds()
+	if expected > 0 && rate > 0 && float64(attempts) < expected {
+		eta := time.Duration((expected - float64(attempts)) / rate * float64(time.Second))
+		fmt.Fprintf(ctx.Stderr, "%d attempts, %.0f/sec, about %s left\n", attempts, rate, eta.Round(time.Second))
+		return
+	}
+	fmt.Fprintf(ctx.Stderr, "%d attempts, %.0f/sec\n", attempts, rate)
+}
+
+// deriveAccount derives the account at index from the mnemonic phrase, reading the passphrase from its source if one is given
+func deriveAccount(ctx *cli.Context, words string, passphraseSpec string, index uint32) (*keypair.Full, error) {
+	seed, e := mnemonicSeed(ctx, words, passphraseSpec)
+	if e != nil {
+		return nil, e
+	}
+	return mnemonic.Account(seed, index)
+}
+
+func mnemonicSeed(ctx *cli.Context, words string, passphraseSpec string) ([]byte, error) {
+	passphrase := ""
+	if passphraseSpec != "" {
+		var e error
+		passphrase, e = secret.Read(passphraseSpec, "mnemonic passphrase", ctx)
+		if e != nil {
+			return nil, e
+		}
+	}
+	return mnemonic.Seed(words, passphrase)
+}
+
+// save encrypts the key pair into the keystore under alias, the seed is never printed
+func save(ctx *cli.Context, alias string, pair *keypair.Full) error {
+	e := secret.CheckAlias(alias)
+	if e != nil {
+		return cli.UsageErrorf("%s", e)
+	}
+	store, e := keystore.Open(keystore.DefaultPath())
+	if e != nil {
+		return e
+	}
+	// fail before asking for a passphrase if the alias is taken
+	if _, ok := store.Get(alias); ok {
+		return fmt.Errorf("alias '%s' already exists in keystore %s", alias, store.Path())
+	}
+
+	passphrase, e := secret.NewPassphrase(ctx)
+	if e != nil {
+		return e
+	}
+	_, e = store.Add(alias, pair.Seed(), []byte(passphrase))
+	if e != nil {
+		return e
+	}
+	e = store.Save()
+	if e != nil {
+		return e
+	}
+	fmt.Fprintf(ctx.Stderr, "saved key '%s' to %s\n", alias, store.Path())
+	return nil
+}
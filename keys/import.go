@@ -0,0 +1,42 @@
+package keys
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/secret"
+)
+
+// ImportCmd saves an existing secret key into the encrypted keystore
+var ImportCmd = &cli.Command{
+	Name:    "import",
+	Summary: "save an existing secret key in the encrypted keystore",
+	Usage:   "-alias <alias> [-secret <source>]",
+	Run:     runImport,
+}
+
+func runImport(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	aliasPtr := fs.String("alias", "", "alias to save the key under")
+	secretPtr := secret.Flag(fs, "secret", "secret key to import")
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	if *aliasPtr == "" {
+		return cli.UsageErrorf("the -alias flag is required")
+	}
+
+	pair, e := secret.LoadKeypair(*secretPtr, ctx)
+	if e != nil {
+		return e
+	}
+	e = save(ctx, *aliasPtr, pair)
+	if e != nil {
+		return e
+	}
+	return ctx.Emit(Key{Address: pair.Address(), Alias: *aliasPtr}, func(w io.Writer) {
+		fmt.Fprintln(w, "Address:", pair.Address())
+	})
+}
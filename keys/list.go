@@ -0,0 +1,40 @@
+package keys
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/keystore"
+)
+
+// ListCmd prints the aliases and addresses in the keystore, it does not need the passphrase
+var ListCmd = &cli.Command{
+	Name:    "list",
+	Summary: "list the keys in the encrypted keystore",
+	Run:     runList,
+}
+
+func runList(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+
+	store, e := keystore.Open(keystore.DefaultPath())
+	if e != nil {
+		return e
+	}
+	keys := []StoredKey{}
+	for _, alias := range store.Aliases() {
+		entry, _ := store.Get(alias)
+		created := entry.Created
+		keys = append(keys, StoredKey{Alias: alias, Address: entry.Address, Created: &created})
+	}
+	return ctx.Emit(keyList{Keys: keys}, func(w io.Writer) {
+		for _, key := range keys {
+			fmt.Fprintf(w, "%-20s %s  %s\n", key.Alias, key.Address, key.Created.Format("2006-01-02"))
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package keys
+
+import (
+	"time"
+)
+
+// Key is a key pair in the JSON output of the keys commands. Seed is only set when the seed would also be printed in
+// text mode, i.e. never for keys saved to the keystore.
+type Key struct {
+	Address  string `json:"address"`
+	Seed     string `json:"seed,omitempty"`
+	Alias    string `json:"alias,omitempty"`
+	Mnemonic string `json:"mnemonic,omitempty"`
+	Path     string `json:"path,omitempty"`
+}
+
+// StoredKey is a keystore entry in the JSON output of the list and check commands, OK and Error are only set by check
+type StoredKey struct {
+	Alias   string     `json:"alias"`
+	Address string     `json:"address,omitempty"`
+	Created *time.Time `json:"created,omitempty"`
+	OK      *bool      `json:"ok,omitempty"`
+	Error   string     `json:"error,omitempty"`
+}
+
+// keyList is the JSON output of the commands that print several keys
+type keyList struct {
+	Keys interface{} `json:"keys"`
+}
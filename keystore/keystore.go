@@ -0,0 +1,268 @@
+// Package keystore stores secret seeds on disk encrypted with a passphrase.
+//
+// Each entry is encrypted on its own using a key derived from the passphrase with scrypt and sealed with NaCl
+// secretbox (XSalsa20-Poly1305), so a wrong passphrase or a tampered entry fails to open instead of yielding garbage.
+// The keystore file is JSON and is only ever written with 0600 permissions.
+package keystore
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// EnvPath overrides the location of the keystore file
+const EnvPath = "STELLAR_KEYSTORE"
+
+// EnvPassphrase supplies the keystore passphrase for unattended use instead of prompting for it
+const EnvPassphrase = "STELLAR_KEYSTORE_PASSPHRASE"
+
+// scrypt parameters for new entries, existing entries keep the parameters they were created with
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	saltLength   = 32
+	keyLength    = 32
+	nonceLength  = 24
+	fileVersion  = 1
+	kdfScrypt    = "scrypt"
+	cipherSecret = "xsalsa20-poly1305"
+)
+
+// ErrWrongPassphrase is returned when an entry cannot be decrypted, either because of a wrong passphrase or because it was tampered with
+var ErrWrongPassphrase = fmt.Errorf("could not decrypt key, wrong passphrase or corrupted entry")
+
+// Entry is a single encrypted key, the address is kept in the clear so keys can be listed without a passphrase
+type Entry struct {
+	Alias      string    `json:"alias"`
+	Address    string    `json:"address"`
+	Created    time.Time `json:"created"`
+	KDF        string    `json:"kdf"`
+	N          int       `json:"n"`
+	R          int       `json:"r"`
+	P          int       `json:"p"`
+	Salt       []byte    `json:"salt"`
+	Cipher     string    `json:"cipher"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+}
+
+// Store is a keystore file loaded in memory
+type Store struct {
+	path    string
+	Version int               `json:"version"`
+	Entries map[string]*Entry `json:"entries"`
+}
+
+// DefaultPath returns $STELLAR_KEYSTORE, falling back to ~/.stellar-go/keystore.json
+func DefaultPath() string {
+	if path := os.Getenv(EnvPath); path != "" {
+		return path
+	}
+	return filepath.Join(os.Getenv("HOME"), ".stellar-go", "keystore.json")
+}
+
+// Open loads the keystore at path, a missing file is treated as an empty keystore
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:    path,
+		Version: fileVersion,
+		Entries: map[string]*Entry{},
+	}
+
+	data, e := ioutil.ReadFile(path)
+	if os.IsNotExist(e) {
+		return s, nil
+	}
+	if e != nil {
+		return nil, e
+	}
+
+	e = json.Unmarshal(data, s)
+	if e != nil {
+		return nil, fmt.Errorf("could not parse keystore %s: %s", path, e)
+	}
+	if s.Version != fileVersion {
+		return nil, fmt.Errorf("unsupported keystore version %d in %s", s.Version, path)
+	}
+	if s.Entries == nil {
+		s.Entries = map[string]*Entry{}
+	}
+	return s, nil
+}
+
+// Path is the location of the keystore file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Aliases lists the aliases in the keystore in sorted order
+func (s *Store) Aliases() []string {
+	aliases := []string{}
+	for alias := range s.Entries {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// Get returns the entry for the alias
+func (s *Store) Get(alias string) (*Entry, bool) {
+	entry, ok := s.Entries[alias]
+	return entry, ok
+}
+
+// Add encrypts the seed with the passphrase and stores it under alias, call Save to persist the change
+func (s *Store) Add(alias string, seed string, passphrase []byte) (*Entry, error) {
+	if alias == "" {
+		return nil, fmt.Errorf("alias cannot be empty")
+	}
+	if _, ok := s.Entries[alias]; ok {
+		return nil, fmt.Errorf("alias '%s' already exists in keystore %s", alias, s.path)
+	}
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("passphrase cannot be empty")
+	}
+	kp, e := keypair.Parse(seed)
+	if e != nil {
+		return nil, e
+	}
+	full, ok := kp.(*keypair.Full)
+	if !ok {
+		return nil, fmt.Errorf("expected a secret seed but got an address")
+	}
+
+	entry := &Entry{
+		Alias:   alias,
+		Address: full.Address(),
+		Created: time.Now().UTC(),
+		KDF:     kdfScrypt,
+		N:       scryptN,
+		R:       scryptR,
+		P:       scryptP,
+		Salt:    make([]byte, saltLength),
+		Cipher:  cipherSecret,
+		Nonce:   make([]byte, nonceLength),
+	}
+	_, e = rand.Read(entry.Salt)
+	if e != nil {
+		return nil, e
+	}
+	_, e = rand.Read(entry.Nonce)
+	if e != nil {
+		return nil, e
+	}
+
+	key, e := entry.deriveKey(passphrase)
+	if e != nil {
+		return nil, e
+	}
+	var nonce [nonceLength]byte
+	copy(nonce[:], entry.Nonce)
+	entry.Ciphertext = secretbox.Seal(nil, []byte(full.Seed()), &nonce, key)
+
+	s.Entries[alias] = entry
+	return entry, nil
+}
+
+// Remove deletes the entry for alias, call Save to persist the change
+func (s *Store) Remove(alias string) error {
+	if _, ok := s.Entries[alias]; !ok {
+		return fmt.Errorf("alias '%s' not found in keystore %s", alias, s.path)
+	}
+	delete(s.Entries, alias)
+	return nil
+}
+
+// Unlock decrypts the entry for alias and returns its key pair
+func (s *Store) Unlock(alias string, passphrase []byte) (*keypair.Full, error) {
+	entry, ok := s.Entries[alias]
+	if !ok {
+		return nil, fmt.Errorf("alias '%s' not found in keystore %s", alias, s.path)
+	}
+	return entry.Unlock(passphrase)
+}
+
+// Unlock decrypts the entry and checks that the seed matches the stored address
+func (entry *Entry) Unlock(passphrase []byte) (*keypair.Full, error) {
+	if entry.KDF != kdfScrypt || entry.Cipher != cipherSecret {
+		return nil, fmt.Errorf("unsupported key derivation '%s' or cipher '%s'", entry.KDF, entry.Cipher)
+	}
+	if len(entry.Nonce) != nonceLength {
+		return nil, ErrWrongPassphrase
+	}
+
+	key, e := entry.deriveKey(passphrase)
+	if e != nil {
+		return nil, e
+	}
+	var nonce [nonceLength]byte
+	copy(nonce[:], entry.Nonce)
+	seed, ok := secretbox.Open(nil, entry.Ciphertext, &nonce, key)
+	if !ok {
+		return nil, ErrWrongPassphrase
+	}
+
+	kp, e := keypair.Parse(string(seed))
+	if e != nil {
+		return nil, ErrWrongPassphrase
+	}
+	full, ok := kp.(*keypair.Full)
+	if !ok || full.Address() != entry.Address {
+		return nil, fmt.Errorf("decrypted key for '%s' does not match the stored address %s", entry.Alias, entry.Address)
+	}
+	return full, nil
+}
+
+// Save writes the keystore back to disk with permissions that only allow the owner to read it
+func (s *Store) Save() error {
+	data, e := json.MarshalIndent(s, "", "  ")
+	if e != nil {
+		return e
+	}
+
+	dir := filepath.Dir(s.path)
+	e = os.MkdirAll(dir, 0700)
+	if e != nil {
+		return e
+	}
+	// write to a temporary file first so a failed write never truncates the existing keystore
+	tmp, e := ioutil.TempFile(dir, ".keystore")
+	if e != nil {
+		return e
+	}
+	defer os.Remove(tmp.Name())
+
+	_, e = tmp.Write(append(data, '\n'))
+	if e == nil {
+		e = tmp.Chmod(0600)
+	}
+	if closeErr := tmp.Close(); e == nil {
+		e = closeErr
+	}
+	if e != nil {
+		return e
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// deriveKey runs the entry's key derivation function over the passphrase
+func (entry *Entry) deriveKey(passphrase []byte) (*[keyLength]byte, error) {
+	derived, e := scrypt.Key(passphrase, entry.Salt, entry.N, entry.R, entry.P, keyLength)
+	if e != nil {
+		return nil, fmt.Errorf("could not derive key: %s", e)
+	}
+	var key [keyLength]byte
+	copy(key[:], derived)
+	return &key, nil
+}
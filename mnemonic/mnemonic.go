@@ -0,0 +1,97 @@
+// Package mnemonic implements SEP-5 key derivation: BIP-39 mnemonic phrases with SLIP-10 ed25519 derivation along
+// m/44'/148'/n', producing the same accounts as other SEP-5 wallets.
+package mnemonic
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/bartekn/go-bip39"
+	"github.com/stellar/go/keypair"
+)
+
+// StellarPathFormat is the SEP-5 derivation path of the account with the given index
+const StellarPathFormat = "m/44'/148'/%d'"
+
+// IndexLimit is the number of accounts that can be derived, SEP-5 indexes are hardened so they must be below 2^31
+const IndexLimit = hardened
+
+const (
+	purpose      = 44
+	coinType     = 148
+	hardened     = 0x80000000
+	masterSecret = "ed25519 seed"
+)
+
+// Generate returns a new random mnemonic phrase with the given number of words, which must be 12, 15, 18, 21 or 24
+func Generate(words int) (string, error) {
+	if words < 12 || words > 24 || words%3 != 0 {
+		return "", fmt.Errorf("invalid number of words %d, must be one of 12, 15, 18, 21 or 24", words)
+	}
+	// every 3 words encode 32 bits of entropy and 1 bit of checksum
+	entropy, e := bip39.NewEntropy(words / 3 * 32)
+	if e != nil {
+		return "", e
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// Normalize collapses the whitespace in a mnemonic phrase and lower-cases it
+func Normalize(mnemonic string) string {
+	return strings.ToLower(strings.Join(strings.Fields(mnemonic), " "))
+}
+
+// Seed validates the mnemonic phrase and returns the BIP-39 seed for it, the passphrase is optional
+func Seed(mnemonic string, passphrase string) ([]byte, error) {
+	mnemonic = Normalize(mnemonic)
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, fmt.Errorf("invalid mnemonic phrase, check the words and their order")
+	}
+	return bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+}
+
+// Account derives the key pair of the account with the given index from a BIP-39 seed
+func Account(seed []byte, index uint32) (*keypair.Full, error) {
+	if index >= hardened {
+		return nil, fmt.Errorf("account index %d is too large", index)
+	}
+	key, chainCode := master(seed)
+	for _, i := range []uint32{purpose, coinType, index} {
+		key, chainCode = child(key, chainCode, i)
+	}
+
+	var rawSeed [32]byte
+	copy(rawSeed[:], key)
+	return keypair.FromRawSeed(rawSeed)
+}
+
+// Path returns the derivation path of the account with the given index
+func Path(index uint32) string {
+	return fmt.Sprintf(StellarPathFormat, index)
+}
+
+// master derives the SLIP-10 ed25519 master key and chain code from the seed
+func master(seed []byte) ([]byte, []byte) {
+	mac := hmac.New(sha512.New, []byte(masterSecret))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
+
+// child derives the hardened child key at index i, ed25519 only supports hardened derivation
+func child(key []byte, chainCode []byte, i uint32) ([]byte, []byte) {
+	data := make([]byte, 0, 37)
+	data = append(data, 0)
+	data = append(data, key...)
+	var index [4]byte
+	binary.BigEndian.PutUint32(index[:], i|hardened)
+	data = append(data, index[:]...)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
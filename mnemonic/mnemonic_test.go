@@ -0,0 +1,133 @@
+package mnemonic
+
+import (
+	"testing"
+)
+
+// sep5Vector is a test vector of SEP-5, the accounts m/44'/148'/0' to m/44'/148'/9' of a mnemonic phrase
+type sep5Vector struct {
+	name       string
+	mnemonic   string
+	passphrase string
+	accounts   [10][2]string
+}
+
+var sep5Vectors = []sep5Vector{
+	{
+		name:     "12 words",
+		mnemonic: "illness spike retreat truth genius clock brain pass fit cave bargain toe",
+		accounts: [10][2]string{
+			{"GDRXE2BQUC3AZNPVFSCEZ76NJ3WWL25FYFK6RGZGIEKWE4SOOHSUJUJ6", "SBGWSG6BTNCKCOB3DIFBGCVMUPQFYPA2G4O34RMTB343OYPXU5DJDVMN"},
+			{"GBAW5XGWORWVFE2XTJYDTLDHXTY2Q2MO73HYCGB3XMFMQ562Q2W2GJQX", "SCEPFFWGAG5P2VX5DHIYK3XEMZYLTYWIPWYEKXFHSK25RVMIUNJ7CTIS"},
+			{"GAY5PRAHJ2HIYBYCLZXTHID6SPVELOOYH2LBPH3LD4RUMXUW3DOYTLXW", "SDAILLEZCSA67DUEP3XUPZJ7NYG7KGVRM46XA7K5QWWUIGADUZCZWTJP"},
+			{"GAOD5NRAEORFE34G5D4EOSKIJB6V4Z2FGPBCJNQI6MNICVITE6CSYIAE", "SBMWLNV75BPI2VB4G27RWOMABVRTSSF7352CCYGVELZDSHCXWCYFKXIX"},
+			{"GBCUXLFLSL2JE3NWLHAWXQZN6SQC6577YMAU3M3BEMWKYPFWXBSRCWV4", "SCPCY3CEHMOP2TADSV2ERNNZBNHBGP4V32VGOORIEV6QJLXD5NMCJUXI"},
+			{"GBRQY5JFN5UBG5PGOSUOL4M6D7VRMAYU6WW2ZWXBMCKB7GPT3YCBU2XZ", "SCK27SFHI3WUDOEMJREV7ZJQG34SCBR6YWCE6OLEXUS2VVYTSNGCRS6X"},
+			{"GBY27SJVFEWR3DUACNBSMJB6T4ZPR4C7ZXSTHT6GMZUDL23LAM5S2PQX", "SDJ4WDPOQAJYR3YIAJOJP3E6E4BMRB7VZ4QAEGCP7EYVDW6NQD3LRJMZ"},
+			{"GAY7T23Z34DWLSTEAUKVBPHHBUE4E3EMZBAQSLV6ZHS764U3TKUSNJOF", "SA3HXJUCE2N27TBIZ5JRBLEBF3TLPQEBINP47E6BTMIWW2RJ5UKR2B3L"},
+			{"GDJTCF62UUYSAFAVIXHPRBR4AUZV6NYJR75INVDXLLRZLZQ62S44443R", "SCD5OSHUUC75MSJG44BAT3HFZL2HZMMQ5M4GPDL7KA6HJHV3FLMUJAME"},
+			{"GBTVYYDIYWGUQUTKX6ZMLGSZGMTESJYJKJWAATGZGITA25ZB6T5REF44", "SCJGVMJ66WAUHQHNLMWDFGY2E72QKSI3XGSBYV6BANDFUFE7VY4XNXXR"},
+		},
+	},
+	{
+		name:     "24 words",
+		mnemonic: "bench hurt jump file august wise shallow faculty impulse spring exact slush thunder author capable act festival slice deposit sauce coconut afford frown better",
+		accounts: [10][2]string{
+			{"GC3MMSXBWHL6CPOAVERSJITX7BH76YU252WGLUOM5CJX3E7UCYZBTPJQ", "SAEWIVK3VLNEJ3WEJRZXQGDAS5NVG2BYSYDFRSH4GKVTS5RXNVED5AX7"},
+			{"GB3MTYFXPBZBUINVG72XR7AQ6P2I32CYSXWNRKJ2PV5H5C7EAM5YYISO", "SBKSABCPDWXDFSZISAVJ5XKVIEWV4M5O3KBRRLSPY3COQI7ZP423FYB4"},
+			{"GDYF7GIHS2TRGJ5WW4MZ4ELIUIBINRNYPPAWVQBPLAZXC2JRDI4DGAKU", "SD5CCQAFRIPB3BWBHQYQ5SC66IB2AVMFNWWPBYGSUXVRZNCIRJ7IHESQ"},
+			{"GAFLH7DGM3VXFVUID7JUKSGOYG52ZRAQPZHQASVCEQERYC5I4PPJUWBD", "SBSGSAIKEF7JYQWQSGXKB4SRHNSKDXTEI33WZDRR6UHYQCQ5I6ZGZQPK"},
+			{"GAXG3LWEXWCAWUABRO6SMAEUKJXLB5BBX6J2KMHFRIWKAMDJKCFGS3NN", "SBIZH53PIRFTPI73JG7QYA3YAINOAT2XMNAUARB3QOWWVZVBAROHGXWM"},
+			{"GA6RUD4DZ2NEMAQY4VZJ4C6K6VSEYEJITNSLUQKLCFHJ2JOGC5UCGCFQ", "SCVM6ZNVRUOP4NMCMMKLTVBEMAF2THIOMHPYSSMPCD2ZU7VDPARQQ6OY"},
+			{"GCUDW6ZF5SCGCMS3QUTELZ6LSAH6IVVXNRPRLAUNJ2XYLCA7KH7ZCVQS", "SBSHUZQNC45IAIRSAHMWJEJ35RY7YNW6SMOEBZHTMMG64NKV7Y52ZEO2"},
+			{"GBJ646Q524WGBN5X5NOAPIF5VQCR2WZCN6QZIDOSY6VA2PMHJ2X636G4", "SC2QO2K2B4EBNBJMBZIKOYSHEX4EZAZNIF4UNLH63AQYV6BE7SMYWC6E"},
+			{"GDHX4LU6YBSXGYTR7SX2P4ZYZSN24VXNJBVAFOB2GEBKNN3I54IYSRM4", "SCGMC5AHAAVB3D4JXQPCORWW37T44XJZUNPEMLRW6DCOEARY3H5MAQST"},
+			{"GDXOY6HXPIDT2QD352CH7VWX257PHVFR72COWQ74QE3TEV4PK2KCKZX7", "SCPA5OX4EYINOPAUEQCPY6TJMYICUS5M7TVXYKWXR3G5ZRAJXY3C37GF"},
+		},
+	},
+	{
+		name:       "24 words with a passphrase",
+		mnemonic:   "cable spray genius state float twenty onion head street palace net private method loan turn phrase state blanket interest dry amazing dress blast tube",
+		passphrase: "p4ssphr4se",
+		accounts: [10][2]string{
+			{"GDAHPZ2NSYIIHZXM56Y36SBVTV5QKFIZGYMMBHOU53ETUSWTP62B63EQ", "SAFWTGXVS7ELMNCXELFWCFZOPMHUZ5LXNBGUVRCY3FHLFPXK4QPXYP2X"},
+			{"GDY47CJARRHHL66JH3RJURDYXAMIQ5DMXZLP3TDAUJ6IN2GUOFX4OJOC", "SBQPDFUGLMWJYEYXFRM5TQX3AX2BR47WKI4FDS7EJQUSEUUVY72MZPJF"},
+			{"GCLAQF5H5LGJ2A6ACOMNEHSWYDJ3VKVBUBHDWFGRBEPAVZ56L4D7JJID", "SAF2LXRW6FOSVQNC4HHIIDURZL4SCGCG7UEGG23ZQG6Q2DKIGMPZV6BZ"},
+			{"GBC36J4KG7ZSIQ5UOSJFQNUP4IBRN6LVUFAHQWT2ODEQ7Y3ASWC5ZN3B", "SDCCVBIYZDMXOR4VPC3IYMIPODNEDZCS44LDN7B5ZWECIE57N3BTV4GQ"},
+			{"GA6NHA4KPH5LFYD6LZH35SIX3DU5CWU3GX6GCKPJPPTQCCQPP627E3CB", "SA5TRXTO7BG2Z6QTQT3O2LC7A7DLZZ2RBTGUNCTG346PLVSSHXPNDVNT"},
+			{"GBOWMXTLABFNEWO34UJNSJJNVEF6ESLCNNS36S5SX46UZT2MNYJOLA5L", "SDEOED2KPHV355YNOLLDLVQB7HDPQVIGKXCAJMA3HTM4325ZHFZSKKUC"},
+			{"GBL3F5JUZN3SQKZ7SL4XSXEJI2SNSVGO6WZWNJLG666WOJHNDDLEXTSZ", "SDYNO6TLFNV3IM6THLNGUG5FII4ET2H7NH3KCT6OAHIUSHKR4XBEEI6A"},
+			{"GA5XPPWXL22HFFL5K5CE37CEPUHXYGSP3NNWGM6IK6K4C3EFHZFKSAND", "SDXMJXAY45W3WEFWMYEPLPIF4CXAD5ECQ37XKMGY5EKLM472SSRJXCYD"},
+			{"GDS5I7L7LWFUVSYVAOHXJET2565MGGHJ4VHGVJXIKVKNO5D4JWXIZ3XU", "SAIZA26BUP55TDCJ4U7I2MSQEAJDPDSZSBKBPWQTD5OQZQSJAGNN2IQB"},
+			{"GBOSMFQYKWFDHJWCMCZSMGUMWCZOM4KFMXXS64INDHVCJ2A2JAABCYRR", "SDXDYPDNRMGOF25AWYYKPHFAD3M54IT7LCLG7RWTGR3TS32A4HTUXNOS"},
+		},
+	},
+}
+
+func TestSEP5Vectors(t *testing.T) {
+	for _, v := range sep5Vectors {
+		seed, e := Seed(v.mnemonic, v.passphrase)
+		if e != nil {
+			t.Fatalf("%s: %s", v.name, e)
+		}
+		for i, want := range v.accounts {
+			pair, e := Account(seed, uint32(i))
+			if e != nil {
+				t.Fatalf("%s, %s: %s", v.name, Path(uint32(i)), e)
+			}
+			if pair.Address() != want[0] || pair.Seed() != want[1] {
+				t.Errorf("%s, %s: got %s %s, want %s %s", v.name, Path(uint32(i)), pair.Address(), pair.Seed(), want[0], want[1])
+			}
+		}
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	v := sep5Vectors[0]
+	seed, e := Seed(v.mnemonic, "p4ssphr4se")
+	if e != nil {
+		t.Fatal(e)
+	}
+	pair, e := Account(seed, 0)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if pair.Address() == v.accounts[0][0] {
+		t.Errorf("the passphrase did not change the derived account %s", pair.Address())
+	}
+}
+
+func TestSeedNormalizes(t *testing.T) {
+	v := sep5Vectors[0]
+	seed, e := Seed("  ILLNESS spike retreat truth genius clock\tbrain pass fit cave bargain toe\n", "")
+	if e != nil {
+		t.Fatal(e)
+	}
+	pair, e := Account(seed, 0)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if pair.Address() != v.accounts[0][0] {
+		t.Errorf("got %s, want %s", pair.Address(), v.accounts[0][0])
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	// the last word breaks the checksum
+	_, e := Seed("illness spike retreat truth genius clock brain pass fit cave bargain bargain", "")
+	if e == nil {
+		t.Error("expected an error for a bad checksum")
+	}
+	seed, e := Seed(sep5Vectors[0].mnemonic, "")
+	if e != nil {
+		t.Fatal(e)
+	}
+	_, e = Account(seed, IndexLimit)
+	if e == nil {
+		t.Errorf("expected an error for index %d", uint(IndexLimit))
+	}
+	_, e = Account(seed, IndexLimit-1)
+	if e != nil {
+		t.Errorf("index %d: %s", uint(IndexLimit-1), e)
+	}
+}
@@ -0,0 +1,56 @@
+// Package offers implements the commands that inspect the order book and manage offers.
+package offers
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kr/pretty"
+	"github.com/nikhilsaraf/stellar-go/cli"
+)
+
+// ListCmd prints the open offers of an account
+var ListCmd = &cli.Command{
+	Name:    "list",
+	Summary: "print the open offers of an account",
+	Usage:   "-a <address>",
+	Run:     runList,
+}
+
+func runList(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	addressPtr := fs.String("a", "", "address - address of the offers to load")
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	if *addressPtr == "" {
+		return cli.UsageErrorf("the -a flag is required")
+	}
+	address := *addressPtr
+
+	p, e := ctx.Profile()
+	if e != nil {
+		return e
+	}
+	fmt.Fprintln(ctx.Info(), "network:", p)
+	fmt.Fprintln(ctx.Info(), "address:", address)
+	fmt.Fprintln(ctx.Info())
+
+	offers, e := p.Client().LoadAccountOffers(address)
+	if e != nil {
+		return e
+	}
+
+	return ctx.Emit(offerList{Offers: newOffers(offers.Embedded.Records)}, func(w io.Writer) {
+		fmt.Fprintln(w, "Offers:")
+		for _, o := range offers.Embedded.Records {
+			pretty.Fprintf(w, "%# v\n\n", o)
+		}
+	})
+}
+
+// offerList is the JSON output of the list command
+type offerList struct {
+	Offers []Offer `json:"offers"`
+}
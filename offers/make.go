@@ -0,0 +1,143 @@
+package offers
+
+import (
+	"fmt"
+
+	"github.com/kr/pretty"
+	"github.com/nikhilsaraf/stellar-go/accounts"
+	"github.com/nikhilsaraf/stellar-go/asset"
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/price"
+	"github.com/nikhilsaraf/stellar-go/secret"
+	"github.com/nikhilsaraf/stellar-go/stroops"
+	"github.com/nikhilsaraf/stellar-go/submit"
+	b "github.com/stellar/go/build"
+)
+
+// MakeCmd creates, updates or deletes an offer
+var MakeCmd = &cli.Command{
+	Name:    "make",
+	Summary: "create, update or delete an offer",
+	Usage:   "[-secret <source>] -sc <code> [-si <issuer>] -bc <code> [-bi <issuer>] -p <price> -amt <amount> (-offerId <id> | -passive) [-dry-run | -unsigned -sequence <n>] [-out <file>]",
+	Run:     runMake,
+}
+
+func runMake(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	secretPtr := secret.Flag(fs, "secret", "source account's secret key")
+	sellingAssetCodePtr := fs.String("sc", "", "sellingCode - code for asset being sold (USD, BTC, native, etc.), or the whole asset as CODE:ISSUER")
+	sellingIssuerCodePtr := fs.String("si", "", "sellingIssuer - if sellingAssetCode is not native, then this needs to be the issuer for the assets being sold")
+	buyingAssetCodePtr := fs.String("bc", "", "buyingCode - code for asset being bought (USD, BTC, native, etc.), or the whole asset as CODE:ISSUER")
+	buyingIssuerCodePtr := fs.String("bi", "", "buyingIssuer - if buyingAssetCode is not native, then this needs to be the issuer for the assets being bought")
+	pricePtr := fs.String("p", "", "price - price of 1 unit of selling in terms of buying, as a decimal or a fraction. For example, if you wanted to sell 30 XLM and buy 5 BTC, the price would be 1/6")
+	amountPtr := stroops.Flag(fs, "amt", "amount - amount of selling being sold, with up to 7 decimal places. Set to 0 if you want to delete an existing offer")
+	passivePtr := fs.Bool("passive", false, "(optional) whether this is a passive offer or not")
+	offerIDPtr := fs.Int("offerId", -1, "(not needed if passive) offerId - the ID of the offer. 0 for new offer. Set to existing offer ID to update or delete")
+	opts := submit.Flags(fs)
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	e = opts.Validate()
+	if e != nil {
+		return e
+	}
+
+	if *sellingAssetCodePtr == "" || *buyingAssetCodePtr == "" || *pricePtr == "" || !stroops.IsSet(fs, "amt") {
+		return cli.UsageErrorf("the -sc, -bc, -p and -amt flags are required and must not be negative")
+	}
+	selling, e := asset.New(*sellingAssetCodePtr, *sellingIssuerCodePtr)
+	if e != nil {
+		return cli.UsageErrorf("invalid selling asset: %s", e)
+	}
+	buying, e := asset.New(*buyingAssetCodePtr, *buyingIssuerCodePtr)
+	if e != nil {
+		return cli.UsageErrorf("invalid buying asset: %s", e)
+	}
+	offerPrice, e := price.Parse(*pricePtr)
+	if e != nil {
+		return cli.UsageErrorf("invalid -p: %s", e)
+	}
+	if !(*passivePtr) && *offerIDPtr < 0 {
+		return cli.UsageErrorf("the -offerId flag is required for offers that are not passive")
+	}
+	if *passivePtr && *offerIDPtr >= 0 {
+		return cli.UsageErrorf("the -offerId flag cannot be used with passive offers")
+	}
+	if *amountPtr == 0 && *offerIDPtr == 0 {
+		return cli.UsageErrorf("cannot delete a new offer, set -offerId to the ID of the offer to delete")
+	}
+
+	p, e := ctx.Profile()
+	if e != nil {
+		return e
+	}
+
+	sourceAddress, sourceKP, e := opts.Signer(*secretPtr, ctx)
+	if e != nil {
+		return e
+	}
+	sellingAsset := selling.Build()
+	buyingAsset := buying.Build()
+	amount := b.Amount(amountPtr.String())
+	passive := *passivePtr
+	offerID := b.OfferID(uint64(*offerIDPtr))
+
+	fmt.Fprintln(ctx.Info(), "network:", p)
+	fmt.Fprintln(ctx.Info(), "sourceAddress:", sourceAddress)
+	fmt.Fprintln(ctx.Info(), "sellingAsset (code, issuer, isNative):", sellingAsset)
+	fmt.Fprintln(ctx.Info(), "buyingAsset (code, issuer, isNative):", buyingAsset)
+	fmt.Fprintln(ctx.Info(), "price (buying per selling):", offerPrice)
+	fmt.Fprintln(ctx.Info(), "inverse price (selling per buying):", offerPrice.Inverse())
+	if offerPrice.RelativeError() > price.WarnError {
+		fmt.Fprintf(ctx.Stderr, "warning: the price %s cannot be stored exactly, %s is off by %.6f%%\n",
+			*pricePtr, offerPrice.Fraction(), offerPrice.RelativeError()*100)
+	}
+	fmt.Fprintln(ctx.Info(), "amount:", amount)
+	fmt.Fprintln(ctx.Info(), "passive:", passive)
+	fmt.Fprintln(ctx.Info(), "offerId:", offerID)
+	fmt.Fprintln(ctx.Info())
+
+	horizonClient := p.Client()
+
+	// validate accounts
+	if !opts.Offline() {
+		_, e = accounts.Load(ctx, horizonClient, sourceAddress, "source")
+		if e != nil {
+			return e
+		}
+	}
+
+	rate := b.Rate{Selling: sellingAsset, Buying: buyingAsset, Price: b.Price(offerPrice.Fraction())}
+	var ob b.ManageOfferBuilder
+	if *amountPtr == 0 {
+		ob = b.DeleteOffer(rate, offerID)
+	} else if passive {
+		ob = b.CreatePassiveOffer(rate, amount)
+	} else if offerID != 0 {
+		ob = b.UpdateOffer(rate, amount, offerID)
+	} else {
+		ob = b.CreateOffer(rate, amount)
+	}
+
+	txn, e := opts.Build(p, horizonClient, sourceAddress,
+		ob,
+	)
+	if e != nil {
+		return e
+	}
+	env, e := txn.Sign()
+	if e != nil {
+		return e
+	}
+	resp, e := opts.Finish(ctx, &env, sourceKP, horizonClient)
+	if e != nil || resp == nil {
+		return e
+	}
+	fmt.Fprintln(ctx.Info(), "response:")
+	pretty.Fprintf(ctx.Info(), "%# v\n", resp)
+
+	// print final balances by reloading accounts
+	_, e = accounts.Load(ctx, horizonClient, sourceAddress, "source")
+	return e
+}
@@ -0,0 +1,121 @@
+package offers_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/horizontest"
+	"github.com/nikhilsaraf/stellar-go/offers"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/keypair"
+)
+
+var root = &cli.Command{Name: "stellar", Subcommands: []*cli.Command{offers.MakeCmd}}
+
+func TestMake(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	seller, _ := keypair.Random()
+	issuer, _ := keypair.Random()
+	s.CreateAccount(seller.Address(), "100")
+	s.CreateAccount(issuer.Address(), "100")
+	usd := "USD:" + issuer.Address()
+	e := s.AddTrustline(seller.Address(), "USD", issuer.Address(), "0", "")
+	if e != nil {
+		t.Fatal(e)
+	}
+	existing := s.AddOffer(horizon.Offer{
+		Seller:  seller.Address(),
+		Selling: horizon.Asset{Type: "native"},
+		Buying:  horizon.Asset{Type: "credit_alphanum4", Code: "USD", Issuer: issuer.Address()},
+		Amount:  "5.0000000",
+		PriceR:  horizon.Price{N: 1, D: 4},
+		Price:   "0.2500000",
+	})
+
+	tests := []struct {
+		name   string
+		args   []string
+		typ    string
+		fields []string
+	}{
+		{
+			"new offer",
+			[]string{"-sc", "native", "-bc", usd, "-p", "1/2", "-amt", "20", "-offerId", "0"},
+			"manage_offer",
+			[]string{"native", usd, "20.0000000", "1/2 (0.5000000)", "0"},
+		},
+		{
+			"passive offer",
+			[]string{"-sc", "USD", "-si", issuer.Address(), "-bc", "XLM", "-p", "4", "-amt", "1.25", "-passive"},
+			"create_passive_offer",
+			[]string{usd, "native", "1.2500000", "4/1 (4.0000000)"},
+		},
+		{
+			"delete offer",
+			[]string{"-sc", "native", "-bc", usd, "-p", "1/4", "-amt", "0", "-offerId", strconv.FormatInt(existing, 10)},
+			"manage_offer",
+			[]string{"native", usd, "0.0000000", "1/4 (0.2500000)", strconv.FormatInt(existing, 10)},
+		},
+	}
+	for i, test := range tests {
+		args := append([]string{"make", "-secret", "stdin"}, test.args...)
+		r := s.Run(root, seller.Seed()+"\n", args...)
+		if r.Code != 0 {
+			t.Fatalf("%s: exit %d: %s", test.name, r.Code, r.Stderr)
+		}
+		txs := s.Transactions()
+		if len(txs) != i+1 {
+			t.Fatalf("%s: got %d transactions, want %d", test.name, len(txs), i+1)
+		}
+		d, e := horizontest.Describe(txs[i].Env)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if d.Source != seller.Address() || len(d.Signatures) != 1 || d.Signatures[0].SignedBy != seller.Address() {
+			t.Errorf("%s: source %s with signatures %+v, want one by %s", test.name, d.Source, d.Signatures, seller.Address())
+		}
+		if len(d.Operations) != 1 || d.Operations[0].Type != test.typ || len(d.Operations[0].Fields) != len(test.fields) {
+			t.Errorf("%s: operations %+v, want a single %s", test.name, d.Operations, test.typ)
+			continue
+		}
+		for j, f := range d.Operations[0].Fields {
+			if f.Value != test.fields[j] {
+				t.Errorf("%s: %s is %s, want %s", test.name, f.Name, f.Value, test.fields[j])
+			}
+		}
+	}
+}
+
+func TestMakeErrors(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	seller, _ := keypair.Random()
+	issuer, _ := keypair.Random()
+	s.CreateAccount(seller.Address(), "100")
+	usd := "USD:" + issuer.Address()
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"missing price", []string{"-sc", "native", "-bc", usd, "-amt", "1", "-offerId", "0"}, 2},
+		{"missing offer ID", []string{"-sc", "native", "-bc", usd, "-p", "1", "-amt", "1"}, 2},
+		{"passive with an offer ID", []string{"-sc", "native", "-bc", usd, "-p", "1", "-amt", "1", "-passive", "-offerId", "0"}, 2},
+		{"deleting a new offer", []string{"-sc", "native", "-bc", usd, "-p", "1", "-amt", "0", "-offerId", "0"}, 2},
+		{"bad price", []string{"-sc", "native", "-bc", usd, "-p", "one", "-amt", "1", "-offerId", "0"}, 2},
+		{"unknown offer", []string{"-sc", "native", "-bc", usd, "-p", "1", "-amt", "1", "-offerId", "999"}, 1},
+	}
+	for _, test := range tests {
+		args := append([]string{"make", "-secret", "stdin"}, test.args...)
+		r := s.Run(root, seller.Seed()+"\n", args...)
+		if r.Code != test.code {
+			t.Errorf("%s: exit %d, want %d: %s", test.name, r.Code, test.code, r.Stderr)
+		}
+	}
+	if txs := s.Transactions(); len(txs) != 0 {
+		t.Errorf("got %d transactions, want none", len(txs))
+	}
+}
@@ -0,0 +1,65 @@
+package offers
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kr/pretty"
+	"github.com/nikhilsaraf/stellar-go/asset"
+	"github.com/nikhilsaraf/stellar-go/cli"
+)
+
+// OrderbookCmd prints the order book for a pair of assets
+var OrderbookCmd = &cli.Command{
+	Name:    "orderbook",
+	Summary: "print the order book for a pair of assets",
+	Usage:   "-sc <code> [-si <issuer>] -bc <code> [-bi <issuer>]",
+	Run:     runOrderbook,
+}
+
+func runOrderbook(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	sellingAssetCodePtr := fs.String("sc", "", "sellingCode - code for asset being sold (USD, BTC, native, etc.), or the whole asset as CODE:ISSUER")
+	sellingIssuerCodePtr := fs.String("si", "", "sellingIssuer - if sellingAssetCode is not native, then this needs to be the issuer for the assets being sold")
+	buyingAssetCodePtr := fs.String("bc", "", "buyingCode - code for asset being bought (USD, BTC, native, etc.), or the whole asset as CODE:ISSUER")
+	buyingIssuerCodePtr := fs.String("bi", "", "buyingIssuer - if buyingAssetCode is not native, then this needs to be the issuer for the assets being bought")
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+
+	if *sellingAssetCodePtr == "" || *buyingAssetCodePtr == "" {
+		return cli.UsageErrorf("the -sc and -bc flags are required")
+	}
+	selling, e := asset.New(*sellingAssetCodePtr, *sellingIssuerCodePtr)
+	if e != nil {
+		return cli.UsageErrorf("invalid selling asset: %s", e)
+	}
+	buying, e := asset.New(*buyingAssetCodePtr, *buyingIssuerCodePtr)
+	if e != nil {
+		return cli.UsageErrorf("invalid buying asset: %s", e)
+	}
+
+	p, e := ctx.Profile()
+	if e != nil {
+		return e
+	}
+
+	sellingAsset := selling.Horizon()
+	buyingAsset := buying.Horizon()
+
+	fmt.Fprintln(ctx.Info(), "network:", p)
+	fmt.Fprintln(ctx.Info(), "sellingAsset (type, code, issuer):", sellingAsset)
+	fmt.Fprintln(ctx.Info(), "buyingAsset (type, code, issuer):", buyingAsset)
+	fmt.Fprintln(ctx.Info())
+
+	orderBook, e := p.Client().LoadOrderBook(sellingAsset, buyingAsset)
+	if e != nil {
+		return e
+	}
+
+	return ctx.Emit(newOrderBook(orderBook), func(w io.Writer) {
+		fmt.Fprintln(w, "OrderBookSummary:")
+		pretty.Fprintf(w, "%# v\n", orderBook)
+	})
+}
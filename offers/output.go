@@ -0,0 +1,71 @@
+package offers
+
+import (
+	"github.com/nikhilsaraf/stellar-go/asset"
+	"github.com/stellar/go/clients/horizon"
+)
+
+// Offer is an open offer in the JSON output of the list command, assets are "native" or "code:issuer"
+type Offer struct {
+	ID      int64  `json:"id"`
+	Seller  string `json:"seller"`
+	Selling string `json:"selling"`
+	Buying  string `json:"buying"`
+	Amount  string `json:"amount"`
+	Price   string `json:"price"`
+	PriceR  Price  `json:"price_r"`
+}
+
+// Price is the exact price of an offer as a fraction
+type Price struct {
+	N int32 `json:"n"`
+	D int32 `json:"d"`
+}
+
+// OrderBook is the JSON output of the orderbook command
+type OrderBook struct {
+	Selling string  `json:"selling"`
+	Buying  string  `json:"buying"`
+	Bids    []Level `json:"bids"`
+	Asks    []Level `json:"asks"`
+}
+
+// Level is the total amount offered at one price in the order book
+type Level struct {
+	Price  string `json:"price"`
+	PriceR Price  `json:"price_r"`
+	Amount string `json:"amount"`
+}
+
+func newOffers(records []horizon.Offer) []Offer {
+	offers := []Offer{}
+	for _, o := range records {
+		offers = append(offers, Offer{
+			ID:      o.ID,
+			Seller:  o.Seller,
+			Selling: asset.HorizonString(o.Selling),
+			Buying:  asset.HorizonString(o.Buying),
+			Amount:  o.Amount,
+			Price:   o.Price,
+			PriceR:  Price{N: o.PriceR.N, D: o.PriceR.D},
+		})
+	}
+	return offers
+}
+
+func newOrderBook(summary horizon.OrderBookSummary) *OrderBook {
+	return &OrderBook{
+		Selling: asset.HorizonString(summary.Selling),
+		Buying:  asset.HorizonString(summary.Buying),
+		Bids:    newLevels(summary.Bids),
+		Asks:    newLevels(summary.Asks),
+	}
+}
+
+func newLevels(levels []horizon.PriceLevel) []Level {
+	result := []Level{}
+	for _, l := range levels {
+		result = append(result, Level{Price: l.Price, PriceR: Price{N: l.PriceR.N, D: l.PriceR.D}, Amount: l.Amount})
+	}
+	return result
+}
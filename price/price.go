@@ -0,0 +1,131 @@
+// Package price parses offer prices given as decimals or fractions and converts them into the fraction of two 32 bit
+// integers that the network stores, using the best approximation when the price cannot be represented exactly.
+package price
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// WarnError is the relative error of the approximation above which commands warn before signing
+const WarnError = 0.000001
+
+// Price is a price as stored by the network, N units of buying for D units of selling
+type Price struct {
+	N int32
+	D int32
+	// Exact is the price that was asked for
+	Exact *big.Rat
+}
+
+// Parse parses a positive decimal such as "0.1667" or fraction such as "1/6" and approximates it
+func Parse(s string) (Price, error) {
+	s = strings.Replace(s, " ", "", -1)
+	exact, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Price{}, fmt.Errorf("'%s' is not a price, expected a decimal such as 0.1667 or a fraction such as 1/6", s)
+	}
+	if exact.Sign() <= 0 {
+		return Price{}, fmt.Errorf("the price %s must be greater than 0", s)
+	}
+	n, d := approximate(exact)
+	if n == 0 {
+		return Price{}, fmt.Errorf("the price %s is too small, the smallest price is 1/%d", s, math.MaxInt32)
+	}
+	if d == 0 {
+		return Price{}, fmt.Errorf("the price %s is too large, the largest price is %d", s, math.MaxInt32)
+	}
+	return Price{N: int32(n), D: int32(d), Exact: exact}, nil
+}
+
+// approximate returns the fraction closest to x with a numerator and denominator of at most math.MaxInt32, from the
+// convergents and semiconvergents of its continued fraction. n is 0 when x is too small and d is 0 when it is too large.
+func approximate(x *big.Rat) (int64, int64) {
+	max := big.NewInt(math.MaxInt32)
+	// h/k are the last two convergents, starting from 0/1 and 1/0
+	h0, h1 := big.NewInt(0), big.NewInt(1)
+	k0, k1 := big.NewInt(1), big.NewInt(0)
+	f := new(big.Rat).Set(x)
+	for {
+		a := new(big.Int).Quo(f.Num(), f.Denom())
+		h := new(big.Int).Add(new(big.Int).Mul(a, h1), h0)
+		k := new(big.Int).Add(new(big.Int).Mul(a, k1), k0)
+		if h.Cmp(max) > 0 || k.Cmp(max) > 0 {
+			// the largest semiconvergent within bounds may be closer than the last convergent
+			t := bound(max, h1, h0)
+			if tk := bound(max, k1, k0); tk != nil && (t == nil || tk.Cmp(t) < 0) {
+				t = tk
+			}
+			if k1.Sign() == 0 {
+				return h1.Int64(), 0
+			}
+			if t != nil && t.Sign() > 0 {
+				sh := new(big.Int).Add(new(big.Int).Mul(t, h1), h0)
+				sk := new(big.Int).Add(new(big.Int).Mul(t, k1), k0)
+				if closer(x, sh, sk, h1, k1) {
+					return sh.Int64(), sk.Int64()
+				}
+			}
+			return h1.Int64(), k1.Int64()
+		}
+		h0, h1 = h1, h
+		k0, k1 = k1, k
+		rest := new(big.Rat).Sub(f, new(big.Rat).SetInt(a))
+		if rest.Sign() == 0 {
+			return h1.Int64(), k1.Int64()
+		}
+		f = rest.Inv(rest)
+	}
+}
+
+// bound returns the largest t with t*p + q <= max, nil when p is 0 and t is unbounded
+func bound(max *big.Int, p *big.Int, q *big.Int) *big.Int {
+	if p.Sign() == 0 {
+		return nil
+	}
+	return new(big.Int).Quo(new(big.Int).Sub(max, q), p)
+}
+
+// closer reports whether a/b is strictly closer to x than c/d
+func closer(x *big.Rat, a *big.Int, b *big.Int, c *big.Int, d *big.Int) bool {
+	e1 := new(big.Rat).Sub(x, new(big.Rat).SetFrac(a, b))
+	e2 := new(big.Rat).Sub(x, new(big.Rat).SetFrac(c, d))
+	return e1.Abs(e1).Cmp(e2.Abs(e2)) < 0
+}
+
+// Rat returns the price as stored by the network
+func (p Price) Rat() *big.Rat {
+	return big.NewRat(int64(p.N), int64(p.D))
+}
+
+// Inverse returns the price of selling in terms of buying
+func (p Price) Inverse() Price {
+	inv := Price{N: p.D, D: p.N}
+	if p.Exact != nil {
+		inv.Exact = new(big.Rat).Inv(p.Exact)
+	}
+	return inv
+}
+
+// RelativeError returns the relative error of the approximation, 0 when the price is exact
+func (p Price) RelativeError() float64 {
+	if p.Exact == nil {
+		return 0
+	}
+	diff := new(big.Rat).Sub(p.Rat(), p.Exact)
+	diff.Quo(diff.Abs(diff), p.Exact)
+	e, _ := diff.Float64()
+	return e
+}
+
+// Fraction formats the price as "n/d", as accepted by the transaction builder
+func (p Price) Fraction() string {
+	return fmt.Sprintf("%d/%d", p.N, p.D)
+}
+
+// String formats the price as a fraction and a decimal, e.g. "1/6 (0.1666667)"
+func (p Price) String() string {
+	return fmt.Sprintf("%s (%s)", p.Fraction(), p.Rat().FloatString(7))
+}
@@ -0,0 +1,106 @@
+package price
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in    string
+		n     int32
+		d     int32
+		exact bool
+	}{
+		{"1/6", 1, 6, true},
+		{"2/4", 1, 2, true},
+		{"0.5", 1, 2, true},
+		{"1.25", 5, 4, true},
+		{"0.1667", 1667, 10000, true},
+		{"3 / 7", 3, 7, true},
+		{"1e3", 1000, 1, true},
+		{"2147483647", 2147483647, 1, true},
+		{"1/2147483647", 1, 2147483647, true},
+		{"2147483647/2147483646", 2147483647, 2147483646, true},
+		// decimals whose fraction is outside the int32 range get the closest fraction within it
+		{"0.1666666667", 1, 6, false},
+		{"1.4142135623730951", 1395591160, 986831973, false},
+		{"2.718281828459045", 919612917, 338306686, false},
+		{"4294967295/4294967296", 1, 1, false},
+		{"2147483646.5", 2147483646, 1, false},
+		{"2147483647.4", 2147483647, 1, false},
+		{"1/2147483648", 1, 2147483647, false},
+	}
+	for _, test := range tests {
+		p, e := Parse(test.in)
+		if e != nil {
+			t.Errorf("%s: %s", test.in, e)
+			continue
+		}
+		if p.N != test.n || p.D != test.d {
+			t.Errorf("%s: got %s, want %d/%d", test.in, p.Fraction(), test.n, test.d)
+		}
+		if exact := p.RelativeError() == 0; exact != test.exact {
+			t.Errorf("%s: relative error %g, want exact %t", test.in, p.RelativeError(), test.exact)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		in  string
+		err string
+	}{
+		{"0", "greater than 0"},
+		{"0/5", "greater than 0"},
+		{"-1", "greater than 0"},
+		{"-1/2", "greater than 0"},
+		{"2147483648", "too large"},
+		{"4294967296/1", "too large"},
+		{"0.0000000001", "too small"},
+		{"1/4294967296", "too small"},
+		{"", "not a price"},
+		{"abc", "not a price"},
+		{"1/0", "not a price"},
+		{"1/2/3", "not a price"},
+	}
+	for _, test := range tests {
+		p, e := Parse(test.in)
+		if e == nil || !strings.Contains(e.Error(), test.err) {
+			t.Errorf("%q: got %s, %v, want error %q", test.in, p.Fraction(), e, test.err)
+		}
+	}
+}
+
+func TestPi(t *testing.T) {
+	p, e := Parse("3.14159265358979")
+	if e != nil {
+		t.Fatal(e)
+	}
+	if p.N != 1434877259 || p.D != 456735617 {
+		t.Errorf("got %s, want 1434877259/456735617", p.Fraction())
+	}
+	// the convergent 245850922/78256779 that a float64 continued fraction stops at is further away
+	convergent := Price{N: 245850922, D: 78256779, Exact: p.Exact}
+	if p.RelativeError() >= convergent.RelativeError() {
+		t.Errorf("relative error %g, the convergent is closer with %g", p.RelativeError(), convergent.RelativeError())
+	}
+	if p.RelativeError() > WarnError {
+		t.Errorf("relative error %g is above the warning threshold", p.RelativeError())
+	}
+}
+
+func TestInverse(t *testing.T) {
+	p, e := Parse("1/6")
+	if e != nil {
+		t.Fatal(e)
+	}
+	inv := p.Inverse()
+	if inv.N != 6 || inv.D != 1 || inv.Exact.Cmp(big.NewRat(6, 1)) != 0 {
+		t.Errorf("got %s, want 6/1", inv)
+	}
+	if got := p.String(); got != "1/6 (0.1666667)" {
+		t.Errorf("got %s", got)
+	}
+}
@@ -0,0 +1,217 @@
+// Package profile resolves named network profiles so every command talks to the same Horizon server and network.
+//
+// Built-in profiles are "testnet", "pubnet" and "local". Additional profiles (for example a standalone network with its
+// own passphrase) can be defined in a TOML config file, and the selected profile can be overridden field by field using
+// environment variables:
+//
+//	default = "standalone"
+//
+//	[networks.standalone]
+//	horizon_url = "http://localhost:8000"
+//	passphrase = "Standalone Network ; February 2017"
+//	friendbot_url = "http://localhost:8000/friendbot"
+//	base_fee = 100
+//
+// The config file is read from $STELLAR_CONFIG, falling back to ~/.stellar-go/config.toml.
+package profile
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/network"
+)
+
+// environment variables that influence profile resolution
+const (
+	EnvConfig       = "STELLAR_CONFIG"
+	EnvNetwork      = "STELLAR_NETWORK"
+	EnvHorizonURL   = "STELLAR_HORIZON_URL"
+	EnvPassphrase   = "STELLAR_NETWORK_PASSPHRASE"
+	EnvFriendbotURL = "STELLAR_FRIENDBOT_URL"
+	EnvBaseFee      = "STELLAR_BASE_FEE"
+)
+
+// DefaultName is the profile used when nothing else is specified
+const DefaultName = "testnet"
+
+// DefaultBaseFee is the base fee (in stroops per operation) used when a profile does not specify one
+const DefaultBaseFee = 100
+
+// Profile describes a Stellar network and the services used to talk to it
+type Profile struct {
+	Name         string `toml:"-"`
+	HorizonURL   string `toml:"horizon_url"`
+	Passphrase   string `toml:"passphrase"`
+	FriendbotURL string `toml:"friendbot_url"`
+	BaseFee      uint64 `toml:"base_fee"`
+}
+
+var builtins = map[string]Profile{
+	"testnet": {
+		HorizonURL:   "https://horizon-testnet.stellar.org",
+		Passphrase:   network.TestNetworkPassphrase,
+		FriendbotURL: "https://horizon-testnet.stellar.org/friendbot",
+		BaseFee:      DefaultBaseFee,
+	},
+	"pubnet": {
+		HorizonURL: "https://horizon.stellar.org",
+		Passphrase: network.PublicNetworkPassphrase,
+		BaseFee:    DefaultBaseFee,
+	},
+	"local": {
+		HorizonURL:   "http://localhost:8000",
+		Passphrase:   network.TestNetworkPassphrase,
+		FriendbotURL: "http://localhost:8000/friendbot",
+		BaseFee:      DefaultBaseFee,
+	},
+}
+
+// config is the structure of the TOML config file
+type config struct {
+	Default  string             `toml:"default"`
+	Networks map[string]Profile `toml:"networks"`
+}
+
+// FlagVar registers the network flag on the given flag set, storing the selected profile name in name
+func FlagVar(fs *flag.FlagSet, name *string) {
+	fs.StringVar(name, "network", *name, "network profile to use (testnet, pubnet, local or one defined in the config file), defaults to $"+EnvNetwork+" or "+DefaultName)
+}
+
+// Load resolves the profile with the given name, an empty name selects $STELLAR_NETWORK, then the config file's default, then DefaultName
+func Load(name string) (*Profile, error) {
+	cfg, e := readConfig()
+	if e != nil {
+		return nil, e
+	}
+
+	if name == "" {
+		name = os.Getenv(EnvNetwork)
+	}
+	if name == "" {
+		name = cfg.Default
+	}
+	if name == "" {
+		name = DefaultName
+	}
+
+	p, ok := cfg.Networks[name]
+	if !ok {
+		p, ok = builtins[name]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown network profile '%s', available profiles: %s", name, strings.Join(names(cfg), ", "))
+	}
+	p.Name = name
+
+	e = p.applyEnv()
+	if e != nil {
+		return nil, e
+	}
+	if p.HorizonURL == "" || p.Passphrase == "" {
+		return nil, fmt.Errorf("network profile '%s' needs both horizon_url and passphrase", name)
+	}
+	if p.BaseFee == 0 {
+		p.BaseFee = DefaultBaseFee
+	}
+	p.HorizonURL = strings.TrimSuffix(p.HorizonURL, "/")
+	return &p, nil
+}
+
+// names lists the names of all known profiles in sorted order
+func names(cfg config) []string {
+	list := []string{}
+	for name := range builtins {
+		list = append(list, name)
+	}
+	for name := range cfg.Networks {
+		if _, ok := builtins[name]; !ok {
+			list = append(list, name)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Network returns the network mutator to use when building transactions
+func (p *Profile) Network() b.Network {
+	return b.Network{Passphrase: p.Passphrase}
+}
+
+// Client returns a Horizon client pointed at the profile's Horizon server
+func (p *Profile) Client() *horizon.Client {
+	return &horizon.Client{
+		URL:  p.HorizonURL,
+		HTTP: http.DefaultClient,
+	}
+}
+
+// IsPublic returns true when the profile points at the public network
+func (p *Profile) IsPublic() bool {
+	return p.Passphrase == network.PublicNetworkPassphrase
+}
+
+// String is the human-readable summary printed by commands
+func (p *Profile) String() string {
+	return fmt.Sprintf("%s (horizon=%s, passphrase='%s')", p.Name, p.HorizonURL, p.Passphrase)
+}
+
+// applyEnv overrides the individual fields of the profile from the environment
+func (p *Profile) applyEnv() error {
+	if v := os.Getenv(EnvHorizonURL); v != "" {
+		p.HorizonURL = v
+	}
+	if v := os.Getenv(EnvPassphrase); v != "" {
+		p.Passphrase = v
+	}
+	if v := os.Getenv(EnvFriendbotURL); v != "" {
+		p.FriendbotURL = v
+	}
+	if v := os.Getenv(EnvBaseFee); v != "" {
+		fee, e := strconv.ParseUint(v, 10, 32)
+		if e != nil {
+			return fmt.Errorf("invalid value for %s: %s", EnvBaseFee, e)
+		}
+		p.BaseFee = fee
+	}
+	return nil
+}
+
+// configPath returns the location of the config file
+func configPath() string {
+	if path := os.Getenv(EnvConfig); path != "" {
+		return path
+	}
+	home := os.Getenv("HOME")
+	if home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".stellar-go", "config.toml")
+}
+
+// readConfig reads the config file, a missing file is the same as an empty one unless it was set explicitly
+func readConfig() (config, error) {
+	cfg := config{}
+	path := configPath()
+	if path == "" {
+		return cfg, nil
+	}
+
+	_, e := toml.DecodeFile(path, &cfg)
+	if os.IsNotExist(e) && os.Getenv(EnvConfig) == "" {
+		return cfg, nil
+	}
+	if e != nil {
+		return cfg, fmt.Errorf("could not read config file %s: %s", path, e)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,312 @@
+// Package secret loads secret seeds from pluggable sources so that commands never need them as plain flag values.
+//
+// A source is selected with a spec string:
+//
+//	prompt            hidden terminal prompt, reads a line from stdin when it is not a terminal (default)
+//	stdin             a single line read from stdin
+//	file:<path>       the contents of a file that only its owner can read
+//	env:<VAR>         the value of an environment variable
+//	cmd:<command>     the output of a command such as a password manager, e.g. "cmd:pass show stellar/issuer"
+//	keystore:<alias>  a key from the encrypted keystore, unlocked with a passphrase
+//	<alias>           shorthand for keystore:<alias>
+//
+// Seeds read from any source are never printed, use Redact on text that may contain one before showing it.
+package secret
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/nikhilsaraf/stellar-go/keystore"
+	"github.com/stellar/go/keypair"
+)
+
+// DefaultSpec is the source used when none is specified
+const DefaultSpec = "prompt"
+
+// Prompter reads input from the user, it is implemented by cli.Context
+type Prompter interface {
+	// ReadLine prints the prompt and reads a line of input
+	ReadLine(prompt string) (string, error)
+	// ReadPassword prints the prompt and reads a line of input without echoing it
+	ReadPassword(prompt string) (string, error)
+}
+
+// Source provides a secret
+type Source interface {
+	// Secret returns the secret value
+	Secret() (string, error)
+	// String describes where the secret comes from without revealing it
+	String() string
+}
+
+// Flag registers a flag that selects the secret source on the given flag set
+func Flag(fs *flag.FlagSet, name string, purpose string) *string {
+	return fs.String(name, DefaultSpec, "source of the "+purpose+": prompt, stdin, file:<path>, env:<VAR>, cmd:<command>, keystore:<alias> or just <alias>")
+}
+
+// Parse returns the source of a secret key described by spec
+func Parse(spec string, prompter Prompter) (Source, error) {
+	return parse(spec, "secret key", prompter)
+}
+
+// Read reads a secret other than a secret key, such as a mnemonic phrase, from the source described by spec, what names
+// the secret in the prompt
+func Read(spec string, what string, prompter Prompter) (string, error) {
+	src, e := parse(spec, what, prompter)
+	if e != nil {
+		return "", e
+	}
+	value, e := src.Secret()
+	if e != nil {
+		return "", fmt.Errorf("could not read %s from %s: %s", what, src, e)
+	}
+	return strings.TrimSpace(value), nil
+}
+
+func parse(spec string, what string, prompter Prompter) (Source, error) {
+	if spec == "" {
+		spec = DefaultSpec
+	}
+	kind, arg := spec, ""
+	if i := strings.Index(spec, ":"); i >= 0 {
+		kind, arg = spec[:i], spec[i+1:]
+	}
+	if seedPattern.MatchString(spec) {
+		return nil, fmt.Errorf("secret seeds cannot be passed on the command line, use one of the secret sources instead")
+	}
+
+	switch kind {
+	case "prompt":
+		return &promptSource{prompter: prompter, what: what}, nil
+	case "stdin":
+		return &stdinSource{prompter: prompter}, nil
+	case "file":
+		return requireArg(&fileSource{path: arg}, kind, arg)
+	case "env":
+		return requireArg(&envSource{name: arg}, kind, arg)
+	case "cmd":
+		return requireArg(&cmdSource{command: arg}, kind, arg)
+	case "keystore":
+		return requireArg(&keystoreSource{alias: arg, prompter: prompter}, kind, arg)
+	}
+	// anything else without a colon is the alias of a keystore key
+	if arg == "" && !strings.Contains(spec, ":") {
+		return &keystoreSource{alias: spec, prompter: prompter}, nil
+	}
+	return nil, fmt.Errorf("unknown secret source '%s'", Redact(spec))
+}
+
+// CheckAlias returns an error if alias cannot be used to refer to a keystore key with a bare -secret <alias>
+func CheckAlias(alias string) error {
+	if alias == "" {
+		return fmt.Errorf("alias cannot be empty")
+	}
+	if strings.ContainsAny(alias, ": \t") {
+		return fmt.Errorf("alias '%s' cannot contain colons or whitespace", alias)
+	}
+	switch alias {
+	case "prompt", "stdin", "file", "env", "cmd", "keystore":
+		return fmt.Errorf("alias '%s' is reserved for a secret source", alias)
+	}
+	if seedPattern.MatchString(alias) {
+		return fmt.Errorf("alias cannot be a secret seed")
+	}
+	return nil
+}
+
+// LoadKeypair reads the secret from the source described by spec and parses it as a secret seed
+func LoadKeypair(spec string, prompter Prompter) (*keypair.Full, error) {
+	src, e := Parse(spec, prompter)
+	if e != nil {
+		return nil, e
+	}
+	value, e := src.Secret()
+	if e != nil {
+		return nil, fmt.Errorf("could not read secret from %s: %s", src, e)
+	}
+
+	kp, e := keypair.Parse(strings.TrimSpace(value))
+	if e != nil {
+		return nil, fmt.Errorf("the secret from %s is not a valid secret seed", src)
+	}
+	full, ok := kp.(*keypair.Full)
+	if !ok {
+		return nil, fmt.Errorf("the secret from %s is an address, expected a secret seed", src)
+	}
+	return full, nil
+}
+
+var seedPattern = regexp.MustCompile(`S[A-Z2-7]{55}`)
+
+// Redact masks anything that looks like a secret seed in s
+func Redact(s string) string {
+	return seedPattern.ReplaceAllStringFunc(s, func(seed string) string {
+		return seed[:1] + strings.Repeat("*", 8)
+	})
+}
+
+func requireArg(src Source, kind string, arg string) (Source, error) {
+	if strings.TrimSpace(arg) == "" {
+		return nil, fmt.Errorf("secret source '%s' needs an argument, e.g. %s:<value>", kind, kind)
+	}
+	return src, nil
+}
+
+type promptSource struct {
+	prompter Prompter
+	what     string
+}
+
+func (s *promptSource) Secret() (string, error) {
+	return s.prompter.ReadPassword("Enter " + s.what + ": ")
+}
+
+func (s *promptSource) String() string {
+	return "prompt"
+}
+
+type stdinSource struct {
+	prompter Prompter
+}
+
+func (s *stdinSource) Secret() (string, error) {
+	return s.prompter.ReadLine("")
+}
+
+func (s *stdinSource) String() string {
+	return "stdin"
+}
+
+type fileSource struct {
+	path string
+}
+
+func (s *fileSource) Secret() (string, error) {
+	info, e := os.Stat(s.path)
+	if e != nil {
+		return "", e
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("permissions %#o on %s are too open, it must only be accessible by its owner (chmod 600)", info.Mode().Perm(), s.path)
+	}
+
+	data, e := ioutil.ReadFile(s.path)
+	if e != nil {
+		return "", e
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func (s *fileSource) String() string {
+	return "file " + s.path
+}
+
+type envSource struct {
+	name string
+}
+
+func (s *envSource) Secret() (string, error) {
+	value, ok := os.LookupEnv(s.name)
+	if !ok || value == "" {
+		return "", fmt.Errorf("environment variable %s is not set", s.name)
+	}
+	return value, nil
+}
+
+func (s *envSource) String() string {
+	return "environment variable " + s.name
+}
+
+type cmdSource struct {
+	command string
+}
+
+func (s *cmdSource) Secret() (string, error) {
+	args := strings.Fields(s.command)
+	if len(args) == 0 {
+		return "", fmt.Errorf("the secret source command is empty")
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	// password managers may need to interact with the user, e.g. for a gpg pin entry
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	e := cmd.Run()
+	if e != nil {
+		return "", e
+	}
+	// only the first line is used, password managers such as pass print extra metadata on the following lines
+	return strings.TrimSpace(strings.SplitN(out.String(), "\n", 2)[0]), nil
+}
+
+func (s *cmdSource) String() string {
+	return "command '" + s.command + "'"
+}
+
+type keystoreSource struct {
+	alias    string
+	prompter Prompter
+}
+
+func (s *keystoreSource) Secret() (string, error) {
+	store, e := keystore.Open(keystore.DefaultPath())
+	if e != nil {
+		return "", e
+	}
+	if _, ok := store.Get(s.alias); !ok {
+		return "", fmt.Errorf("alias '%s' not found in keystore %s", s.alias, store.Path())
+	}
+
+	passphrase, e := Passphrase(s.prompter, "Enter passphrase for key '"+s.alias+"': ")
+	if e != nil {
+		return "", e
+	}
+	kp, e := store.Unlock(s.alias, []byte(passphrase))
+	if e != nil {
+		return "", e
+	}
+	return kp.Seed(), nil
+}
+
+func (s *keystoreSource) String() string {
+	return "keystore key '" + s.alias + "'"
+}
+
+// Passphrase returns the keystore passphrase from $STELLAR_KEYSTORE_PASSPHRASE, prompting for it when unset
+func Passphrase(prompter Prompter, prompt string) (string, error) {
+	if passphrase := os.Getenv(keystore.EnvPassphrase); passphrase != "" {
+		return passphrase, nil
+	}
+	return prompter.ReadPassword(prompt)
+}
+
+// NewPassphrase returns the passphrase for a new keystore key from $STELLAR_KEYSTORE_PASSPHRASE, prompting for it twice when unset
+func NewPassphrase(prompter Prompter) (string, error) {
+	if passphrase := os.Getenv(keystore.EnvPassphrase); passphrase != "" {
+		return passphrase, nil
+	}
+	passphrase, e := prompter.ReadPassword("Enter new keystore passphrase: ")
+	if e != nil {
+		return "", e
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase cannot be empty")
+	}
+	confirm, e := prompter.ReadPassword("Repeat passphrase: ")
+	if e != nil {
+		return "", e
+	}
+	if confirm != passphrase {
+		return "", fmt.Errorf("passphrases do not match")
+	}
+	return passphrase, nil
+}
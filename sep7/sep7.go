@@ -0,0 +1,131 @@
+/*
+Copyright 2018 Lightyear.io
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sep7 parses, validates, builds, signs and verifies SEP-7 URI requests such as
+// web+stellar:pay?destination=G...&amount=10.
+package sep7
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+
+	"github.com/nikhilsaraf/stellar-go/stellartoml"
+	"github.com/stellar/go/keypair"
+)
+
+// signaturePrefix is the standardized namespace prefix for signing URI requests
+const signaturePrefix = "stellar.sep.7 - URI Scheme"
+
+// Payload returns the bytes that are signed for the URI request: 35 zero bytes and a 4, which denote application-based
+// signing, followed by the namespace prefix and the URI request
+func Payload(uri string) []byte {
+	var prefixSelectorBytes [36]byte
+	prefixSelectorBytes[35] = 4
+
+	result := make([]byte, 0, len(prefixSelectorBytes)+len(signaturePrefix)+len(uri))
+	result = append(result, prefixSelectorBytes[:]...)
+	result = append(result, signaturePrefix...)
+	result = append(result, uri...)
+	return result
+}
+
+// Sign signs the URI request with the secret seed and returns the url-encoded base64 signature, ready to be appended to
+// the request as the signature parameter
+func Sign(uri string, seed string) (string, error) {
+	kp, e := keypair.Parse(seed)
+	if e != nil {
+		return "", e
+	}
+	signatureBytes, e := kp.Sign(Payload(uri))
+	if e != nil {
+		return "", e
+	}
+	return url.QueryEscape(base64.StdEncoding.EncodeToString(signatureBytes)), nil
+}
+
+// Sign signs the request with the secret seed, replacing any previous signature, and returns the signed request. The
+// signature covers the request as parsed when there is one, so that its parameters keep their encoding.
+func (u *URI) Sign(seed string) (string, error) {
+	unsigned := u.Unsigned()
+	urlEncodedSignature, e := Sign(unsigned, seed)
+	if e != nil {
+		return "", e
+	}
+	u.Signature, e = url.QueryUnescape(urlEncodedSignature)
+	if e != nil {
+		return "", e
+	}
+	u.raw = unsigned + "&signature=" + urlEncodedSignature
+	return u.raw, nil
+}
+
+// VerifySignature checks the signature of the request against the address of the signer
+func (u *URI) VerifySignature(address string) error {
+	if u.Signature == "" {
+		return fmt.Errorf("the URI request is not signed")
+	}
+	kp, e := keypair.Parse(address)
+	if e != nil {
+		return e
+	}
+	signatureBytes, e := base64.StdEncoding.DecodeString(u.Signature)
+	if e != nil {
+		return fmt.Errorf("the signature is not base64 encoded: %s", e)
+	}
+	e = kp.Verify(Payload(u.Unsigned()), signatureBytes)
+	if e != nil {
+		return fmt.Errorf("the signature of the URI request does not match %s, the request was changed after it was signed or signed by someone else", address)
+	}
+	return nil
+}
+
+// VerifyOrigin checks the signature of the request against the URI_REQUEST_SIGNING_KEY published in the stellar.toml of
+// its origin_domain and returns that key, client may be nil to use http.DefaultClient
+func (u *URI) VerifyOrigin(client stellartoml.HTTP) (string, error) {
+	if u.OriginDomain == "" {
+		return "", fmt.Errorf("the URI request has no origin_domain to verify its signature against")
+	}
+	toml, e := stellartoml.Get(client, u.OriginDomain)
+	if e != nil {
+		return "", e
+	}
+	if toml.URIRequestSigningKey == "" {
+		return "", fmt.Errorf("the stellar.toml of %s has no URI_REQUEST_SIGNING_KEY", u.OriginDomain)
+	}
+	e = u.VerifySignature(toml.URIRequestSigningKey)
+	if e != nil {
+		return "", e
+	}
+	return toml.URIRequestSigningKey, nil
+}
+
+// Verify checks the url-encoded base64 signature of the URI request against the address of the signer
+func Verify(uri string, urlEncodedBase64Signature string, address string) error {
+	kp, e := keypair.Parse(address)
+	if e != nil {
+		return e
+	}
+	base64Signature, e := url.QueryUnescape(urlEncodedBase64Signature)
+	if e != nil {
+		return e
+	}
+	signatureBytes, e := base64.StdEncoding.DecodeString(base64Signature)
+	if e != nil {
+		return e
+	}
+	return kp.Verify(Payload(uri), signatureBytes)
+}
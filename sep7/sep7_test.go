@@ -0,0 +1,135 @@
+package sep7
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsaraf/stellar-go/horizontest"
+)
+
+// the signed request of the SEP-7 specification
+const (
+	specRequest   = "web+stellar:pay?destination=GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO&amount=120.1234567&memo=skdjfasf&memo_type=MEMO_TEXT&msg=pay%20me%20with%20lumens&origin_domain=someDomain.com"
+	specSeed      = "SBPOVRVKTTV7W3IOX2FJPSMPCJ5L2WU2YKTP3HCLYPXNI5MDIGREVNYC"
+	specAddress   = "GD7ACHBPHSC5OJMJZZBXA7Z5IAUFTH6E6XVLNBPASDQYJ7LO5UIYBDQW"
+	specSignature = "tbsLtlK%2FfouvRWk2UWFP47yHYeI1g1NEC%2FfEQvuXG6V8P%2BbeLxplYbOVtTk1g94Wp97cHZ3pVJy%2FtZNYobl3Cw%3D%3D"
+)
+
+func TestPayload(t *testing.T) {
+	payload := Payload("web+stellar:tx")
+	want := append(make([]byte, 35), 4)
+	want = append(want, "stellar.sep.7 - URI Schemeweb+stellar:tx"...)
+	if !bytes.Equal(payload, want) {
+		t.Errorf("got %q, want %q", payload, want)
+	}
+}
+
+func TestSign(t *testing.T) {
+	signature, e := Sign(specRequest, specSeed)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if signature != specSignature {
+		t.Errorf("got %s, want %s", signature, specSignature)
+	}
+	e = Verify(specRequest, signature, specAddress)
+	if e != nil {
+		t.Error(e)
+	}
+
+	_, e = Sign(specRequest, specAddress)
+	if e == nil {
+		t.Error("expected an error when signing with an address")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		address string
+		valid   bool
+	}{
+		{"spec example", specRequest + "&signature=" + specSignature, specAddress, true},
+		{"other signer", specRequest + "&signature=" + specSignature, "GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO", false},
+		{"changed amount", "web+stellar:pay?destination=GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO&amount=1200.1234567&memo=skdjfasf&memo_type=MEMO_TEXT&msg=pay%20me%20with%20lumens&origin_domain=someDomain.com&signature=" + specSignature, specAddress, false},
+		{"unsigned", "web+stellar:pay?destination=GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO", specAddress, false},
+	}
+	for _, test := range tests {
+		u, e := Parse(test.uri)
+		if e != nil {
+			t.Fatalf("%s: %s", test.name, e)
+		}
+		e = u.VerifySignature(test.address)
+		if test.valid && e != nil {
+			t.Errorf("%s: %s", test.name, e)
+		} else if !test.valid && e == nil {
+			t.Errorf("%s: expected the signature to be rejected", test.name)
+		}
+	}
+}
+
+func TestURISign(t *testing.T) {
+	u, e := ParseUnsigned(specRequest)
+	if e != nil {
+		t.Fatal(e)
+	}
+	signed, e := u.Sign(specSeed)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if want := specRequest + "&signature=" + specSignature; signed != want {
+		t.Errorf("got %s, want %s", signed, want)
+	}
+	e = u.VerifySignature(specAddress)
+	if e != nil {
+		t.Error(e)
+	}
+}
+
+func TestVerifyOrigin(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	u, e := Parse(specRequest + "&signature=" + specSignature)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	tests := []struct {
+		name       string
+		signingKey string
+		err        string
+	}{
+		{"no URI_REQUEST_SIGNING_KEY", "", "has no URI_REQUEST_SIGNING_KEY"},
+		{"other signing key", "GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO", "does not match"},
+		{"signing key of the signer", specAddress, ""},
+	}
+	for _, test := range tests {
+		s.SetURIRequestSigningKey(test.signingKey)
+		key, e := u.VerifyOrigin(s.HTTPClient())
+		if test.err != "" {
+			if e == nil || !strings.Contains(e.Error(), test.err) {
+				t.Errorf("%s: got error %v, want %q", test.name, e, test.err)
+			}
+			continue
+		}
+		if e != nil {
+			t.Errorf("%s: %s", test.name, e)
+			continue
+		}
+		if key != specAddress {
+			t.Errorf("%s: verified with %s, want %s", test.name, key, specAddress)
+		}
+	}
+
+	// a request without an origin_domain has nothing to verify against
+	u, e = Parse("web+stellar:pay?destination=GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO")
+	if e != nil {
+		t.Fatal(e)
+	}
+	_, e = u.VerifyOrigin(s.HTTPClient())
+	if e == nil || !strings.Contains(e.Error(), "no origin_domain") {
+		t.Errorf("got error %v, want the missing origin_domain to be reported", e)
+	}
+}
@@ -0,0 +1,517 @@
+package sep7
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsaraf/stellar-go/asset"
+	"github.com/nikhilsaraf/stellar-go/envelope"
+	"github.com/nikhilsaraf/stellar-go/stroops"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// Scheme is the scheme of every URI request
+const Scheme = "web+stellar"
+
+// operations of a URI request
+const (
+	// OperationTx asks the wallet to sign, and possibly change, a transaction
+	OperationTx = "tx"
+	// OperationPay asks the wallet to pay a destination
+	OperationPay = "pay"
+)
+
+// memo types of a pay request
+const (
+	MemoText   = "MEMO_TEXT"
+	MemoID     = "MEMO_ID"
+	MemoHash   = "MEMO_HASH"
+	MemoReturn = "MEMO_RETURN"
+)
+
+// limits set by SEP-7
+const (
+	// MaxMsgLength is the longest msg in characters
+	MaxMsgLength = 300
+	// MaxChainDepth is the deepest nesting of chain parameters
+	MaxChainDepth = 7
+	// maxMemoText is the longest text memo in bytes
+	maxMemoText = 28
+	// callbackPrefix is the prefix of callbacks that are URLs, the only kind defined so far
+	callbackPrefix = "url:"
+)
+
+// params lists the parameters of each operation in the order String writes them, signature always comes last
+var params = map[string][]string{
+	OperationTx:  {"xdr", "replace", "callback", "pubkey", "chain", "msg", "network_passphrase", "origin_domain", "signature"},
+	OperationPay: {"destination", "amount", "asset_code", "asset_issuer", "memo", "memo_type", "callback", "msg", "network_passphrase", "origin_domain", "signature"},
+}
+
+// URI is a parsed SEP-7 URI request, empty fields are parameters that were not given
+type URI struct {
+	// Operation is OperationTx or OperationPay
+	Operation string
+
+	// XDR is the base64 encoded transaction envelope of a tx request
+	XDR string
+	// Replace lists the fields of the transaction the wallet should fill in
+	Replace []Replacement
+	// Pubkey is the account that should sign the transaction
+	Pubkey string
+	// Chain is the URI request that this request was generated from
+	Chain string
+
+	// Destination is the account ID or federation address to pay
+	Destination string
+	// Amount is the amount to pay, the wallet asks for it when empty
+	Amount string
+	// AssetCode and AssetIssuer are the asset to pay with, lumens when empty
+	AssetCode   string
+	AssetIssuer string
+	// Memo and MemoType are the memo to attach to the payment, the memo of hashes is base64 encoded
+	Memo     string
+	MemoType string
+
+	// Callback is the URL the signed transaction should be posted to instead of being submitted, without the url: prefix
+	Callback string
+	// Msg is a message for the user
+	Msg string
+	// NetworkPassphrase is the passphrase of the network, the public network when empty
+	NetworkPassphrase string
+	// OriginDomain is the domain that signed the request
+	OriginDomain string
+	// Signature is the base64 encoded signature of the request by the URI_REQUEST_SIGNING_KEY of OriginDomain
+	Signature string
+
+	// raw is the request as parsed, its signature covers the exact bytes that were received
+	raw string
+}
+
+// Replacement is one entry of the replace parameter: the field at Path, in SEP-11 Txrep notation such as
+// "sourceAccount" or "operations[0].destination", is to be replaced with the value identified by ID and described
+// by Hint
+type Replacement struct {
+	Path string
+	ID   string
+	Hint string
+}
+
+// Parse parses and validates a URI request
+func Parse(s string) (*URI, error) {
+	return parse(s, 0)
+}
+
+// ParseUnsigned parses and validates a URI request that is about to be signed: any signature is dropped and the
+// origin_domain may be given without one
+func ParseUnsigned(s string) (*URI, error) {
+	return decode(stripSignature(s), 0)
+}
+
+// parse decodes a request as received, whose origin_domain must come with a signature
+func parse(s string, depth int) (*URI, error) {
+	u, e := decode(s, depth)
+	if e != nil {
+		return nil, e
+	}
+	if u.OriginDomain != "" && u.Signature == "" {
+		return nil, fmt.Errorf("the origin_domain %s needs a signature", u.OriginDomain)
+	}
+	return u, nil
+}
+
+// decode parses and validates a request without requiring a signature
+func decode(s string, depth int) (*URI, error) {
+	prefix := Scheme + ":"
+	if !strings.HasPrefix(s, prefix) {
+		return nil, fmt.Errorf("not a SEP-7 URI request, it does not start with %s", prefix)
+	}
+	rest := s[len(prefix):]
+	operation, query := rest, ""
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		operation, query = rest[:i], rest[i+1:]
+	}
+	names, ok := params[operation]
+	if !ok {
+		return nil, fmt.Errorf("unknown operation '%s', expected %s or %s", operation, OperationTx, OperationPay)
+	}
+
+	values := map[string]string{}
+	var order []string
+	for _, pair := range strings.Split(query, "&") {
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		name, e := url.QueryUnescape(kv[0])
+		if e != nil {
+			return nil, fmt.Errorf("invalid parameter name '%s': %s", kv[0], e)
+		}
+		value := ""
+		if len(kv) == 2 {
+			value, e = url.QueryUnescape(kv[1])
+			if e != nil {
+				return nil, fmt.Errorf("invalid value of %s: %s", name, e)
+			}
+		}
+		if !contains(names, name) {
+			return nil, fmt.Errorf("unknown parameter '%s' for the %s operation", name, operation)
+		}
+		if _, ok := values[name]; ok {
+			return nil, fmt.Errorf("the parameter %s is given more than once", name)
+		}
+		values[name] = value
+		order = append(order, name)
+	}
+	if _, ok := values["signature"]; ok && order[len(order)-1] != "signature" {
+		return nil, fmt.Errorf("the signature must be the last parameter")
+	}
+
+	u := &URI{
+		Operation:         operation,
+		XDR:               values["xdr"],
+		Pubkey:            values["pubkey"],
+		Chain:             values["chain"],
+		Destination:       values["destination"],
+		Amount:            values["amount"],
+		AssetCode:         values["asset_code"],
+		AssetIssuer:       values["asset_issuer"],
+		Memo:              values["memo"],
+		MemoType:          values["memo_type"],
+		Msg:               values["msg"],
+		NetworkPassphrase: values["network_passphrase"],
+		OriginDomain:      values["origin_domain"],
+		Signature:         values["signature"],
+		raw:               s,
+	}
+	if callback, ok := values["callback"]; ok {
+		if !strings.HasPrefix(callback, callbackPrefix) {
+			return nil, fmt.Errorf("unsupported callback '%s', only callbacks starting with %s are defined", callback, callbackPrefix)
+		}
+		u.Callback = strings.TrimPrefix(callback, callbackPrefix)
+	}
+	if replace, ok := values["replace"]; ok {
+		var e error
+		u.Replace, e = parseReplace(replace)
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	e := u.validate(depth)
+	if e != nil {
+		return nil, e
+	}
+	return u, nil
+}
+
+// Validate checks the parameters of the request against SEP-7
+func (u *URI) Validate() error {
+	return u.validate(0)
+}
+
+func (u *URI) validate(depth int) error {
+	switch u.Operation {
+	case OperationTx:
+		e := u.validateTx(depth)
+		if e != nil {
+			return e
+		}
+	case OperationPay:
+		e := u.validatePay()
+		if e != nil {
+			return e
+		}
+	default:
+		return fmt.Errorf("unknown operation '%s', expected %s or %s", u.Operation, OperationTx, OperationPay)
+	}
+
+	// the signed transaction is posted to the callback, so it must not travel in the clear
+	if u.Callback != "" {
+		cb, e := url.Parse(u.Callback)
+		if e != nil || cb.Scheme != "https" || cb.Host == "" {
+			return fmt.Errorf("the callback '%s' is not an https URL", u.Callback)
+		}
+	}
+	if n := len([]rune(u.Msg)); n > MaxMsgLength {
+		return fmt.Errorf("the msg is %d characters long, the limit is %d", n, MaxMsgLength)
+	}
+	if u.OriginDomain != "" {
+		if !isDomain(u.OriginDomain) {
+			return fmt.Errorf("the origin_domain '%s' is not a fully qualified domain name", u.OriginDomain)
+		}
+	}
+	if u.Signature != "" {
+		sig, e := base64.StdEncoding.DecodeString(u.Signature)
+		if e != nil || len(sig) != 64 {
+			return fmt.Errorf("the signature is not a base64 encoded ed25519 signature")
+		}
+	}
+	return nil
+}
+
+func (u *URI) validateTx(depth int) error {
+	if u.Destination != "" || u.Amount != "" || u.AssetCode != "" || u.AssetIssuer != "" || u.Memo != "" || u.MemoType != "" {
+		return fmt.Errorf("the parameters of a pay request cannot be used with the %s operation", OperationTx)
+	}
+	if u.XDR == "" {
+		return fmt.Errorf("the xdr parameter is required for the %s operation", OperationTx)
+	}
+	_, e := envelope.Decode(u.XDR)
+	if e != nil {
+		return fmt.Errorf("invalid xdr: %s", e)
+	}
+	for _, r := range u.Replace {
+		if r.Path == "" || r.ID == "" {
+			return fmt.Errorf("invalid replace entry '%s:%s', expected path:id", r.Path, r.ID)
+		}
+	}
+	if u.Pubkey != "" && !isAccountID(u.Pubkey) {
+		return fmt.Errorf("the pubkey '%s' is not a valid account ID", u.Pubkey)
+	}
+	if u.Chain != "" {
+		if depth >= MaxChainDepth {
+			return fmt.Errorf("the chain is nested more than %d levels deep", MaxChainDepth)
+		}
+		_, e = parse(u.Chain, depth+1)
+		if e != nil {
+			return fmt.Errorf("invalid chain: %s", e)
+		}
+	}
+	return nil
+}
+
+func (u *URI) validatePay() error {
+	if u.XDR != "" || len(u.Replace) > 0 || u.Pubkey != "" || u.Chain != "" {
+		return fmt.Errorf("the parameters of a tx request cannot be used with the %s operation", OperationPay)
+	}
+	if u.Destination == "" {
+		return fmt.Errorf("the destination parameter is required for the %s operation", OperationPay)
+	}
+	if !isAccountID(u.Destination) && !strings.Contains(u.Destination, "*") {
+		return fmt.Errorf("the destination '%s' is neither an account ID nor a federation address", u.Destination)
+	}
+	if u.Amount != "" {
+		a, e := stroops.Parse(u.Amount)
+		if e != nil {
+			return fmt.Errorf("invalid amount: %s", e)
+		}
+		if a == 0 {
+			return fmt.Errorf("the amount must be greater than 0")
+		}
+	}
+	if u.AssetCode != "" || u.AssetIssuer != "" {
+		if u.AssetCode == "" || u.AssetIssuer == "" {
+			return fmt.Errorf("asset_code and asset_issuer must be given together")
+		}
+		_, e := asset.New(u.AssetCode, u.AssetIssuer)
+		if e != nil {
+			return fmt.Errorf("invalid asset: %s", e)
+		}
+	}
+	return u.validateMemo()
+}
+
+func (u *URI) validateMemo() error {
+	if u.Memo == "" {
+		if u.MemoType != "" {
+			return fmt.Errorf("the memo_type %s needs a memo", u.MemoType)
+		}
+		return nil
+	}
+	switch u.MemoType {
+	case "", MemoText:
+		if len(u.Memo) > maxMemoText {
+			return fmt.Errorf("the text memo is longer than %d bytes", maxMemoText)
+		}
+	case MemoID:
+		_, e := strconv.ParseUint(u.Memo, 10, 64)
+		if e != nil {
+			return fmt.Errorf("the %s '%s' is not a 64 bit unsigned integer", MemoID, u.Memo)
+		}
+	case MemoHash, MemoReturn:
+		hash, e := base64.StdEncoding.DecodeString(u.Memo)
+		if e != nil || len(hash) != 32 {
+			return fmt.Errorf("the %s must be 32 base64 encoded bytes", u.MemoType)
+		}
+	default:
+		return fmt.Errorf("unknown memo_type '%s', expected %s, %s, %s or %s", u.MemoType, MemoText, MemoID, MemoHash, MemoReturn)
+	}
+	return nil
+}
+
+// MemoMutator returns the memo of a pay request for the transaction builder, nil when there is none
+func (u *URI) MemoMutator() (b.TransactionMutator, error) {
+	e := u.validateMemo()
+	if e != nil || u.Memo == "" {
+		return nil, e
+	}
+	switch u.MemoType {
+	case MemoID:
+		id, _ := strconv.ParseUint(u.Memo, 10, 64)
+		return b.MemoID{Value: id}, nil
+	case MemoHash, MemoReturn:
+		raw, _ := base64.StdEncoding.DecodeString(u.Memo)
+		var hash xdr.Hash
+		copy(hash[:], raw)
+		if u.MemoType == MemoReturn {
+			return b.MemoReturn{Value: hash}, nil
+		}
+		return b.MemoHash{Value: hash}, nil
+	}
+	return b.MemoText{Value: u.Memo}, nil
+}
+
+// Passphrase returns the passphrase of the network the request is for
+func (u *URI) Passphrase() string {
+	if u.NetworkPassphrase == "" {
+		return network.PublicNetworkPassphrase
+	}
+	return u.NetworkPassphrase
+}
+
+// String builds the URI request with its parameters in the order SEP-7 lists them
+func (u *URI) String() string {
+	values := map[string]string{
+		"xdr":                u.XDR,
+		"replace":            formatReplace(u.Replace),
+		"pubkey":             u.Pubkey,
+		"chain":              u.Chain,
+		"destination":        u.Destination,
+		"amount":             u.Amount,
+		"asset_code":         u.AssetCode,
+		"asset_issuer":       u.AssetIssuer,
+		"memo":               u.Memo,
+		"memo_type":          u.MemoType,
+		"msg":                u.Msg,
+		"network_passphrase": u.NetworkPassphrase,
+		"origin_domain":      u.OriginDomain,
+		"signature":          u.Signature,
+	}
+	if u.Callback != "" {
+		values["callback"] = callbackPrefix + u.Callback
+	}
+
+	var query []string
+	for _, name := range params[u.Operation] {
+		if v := values[name]; v != "" {
+			query = append(query, name+"="+escape(v))
+		}
+	}
+	return Scheme + ":" + u.Operation + "?" + strings.Join(query, "&")
+}
+
+// Unsigned returns the request without its signature, the payload that the signature covers. It is taken from the
+// request as parsed when there is one, since rebuilding it may encode the parameters differently.
+func (u *URI) Unsigned() string {
+	s := u.raw
+	if s == "" {
+		s = u.String()
+	}
+	return stripSignature(s)
+}
+
+// stripSignature removes the signature parameter, which SEP-7 requires to be the last one
+func stripSignature(s string) string {
+	if i := strings.LastIndex(s, "&signature="); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// parseReplace parses a replace parameter such as
+// "sourceAccount:X,operations[0].destination:Y;X:account to pay from,Y:account to pay"
+func parseReplace(s string) ([]Replacement, error) {
+	fields, hints := s, ""
+	if i := strings.IndexByte(s, ';'); i >= 0 {
+		fields, hints = s[:i], s[i+1:]
+	}
+
+	descriptions := map[string]string{}
+	for _, h := range strings.Split(hints, ",") {
+		if h == "" {
+			continue
+		}
+		kv := strings.SplitN(h, ":", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid replace hint '%s', expected id:hint", h)
+		}
+		descriptions[kv[0]] = kv[1]
+	}
+
+	var list []Replacement
+	for _, f := range strings.Split(fields, ",") {
+		if f == "" {
+			continue
+		}
+		kv := strings.SplitN(f, ":", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, fmt.Errorf("invalid replace field '%s', expected path:id", f)
+		}
+		list = append(list, Replacement{Path: kv[0], ID: kv[1], Hint: descriptions[kv[1]]})
+	}
+	return list, nil
+}
+
+// formatReplace is the inverse of parseReplace, every hint is listed once in the order of first use
+func formatReplace(list []Replacement) string {
+	if len(list) == 0 {
+		return ""
+	}
+	var fields, hints []string
+	seen := map[string]bool{}
+	for _, r := range list {
+		fields = append(fields, r.Path+":"+r.ID)
+		if r.Hint != "" && !seen[r.ID] {
+			seen[r.ID] = true
+			hints = append(hints, r.ID+":"+r.Hint)
+		}
+	}
+	if len(hints) == 0 {
+		return strings.Join(fields, ",")
+	}
+	return strings.Join(fields, ",") + ";" + strings.Join(hints, ",")
+}
+
+// escape url-encodes a parameter value the way the SEP-7 examples do, with spaces as %20
+func escape(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
+
+func isAccountID(s string) bool {
+	_, e := strkey.Decode(strkey.VersionByteAccountID, s)
+	return e == nil
+}
+
+// isDomain reports whether s looks like a fully qualified domain name such as example.com
+func isDomain(s string) bool {
+	labels := strings.Split(s, ".")
+	if len(labels) < 2 || len(s) > 253 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package sep7
+
+import (
+	"strings"
+	"testing"
+
+	b "github.com/stellar/go/build"
+)
+
+const bob = "GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO"
+
+// txXDR builds an unsigned payment from the spec signer to bob and returns its envelope
+func txXDR(t *testing.T) string {
+	txn, e := b.Transaction(
+		b.SourceAccount{AddressOrSeed: specAddress},
+		b.Sequence{Sequence: 5},
+		b.TestNetwork,
+		b.Payment(
+			b.Destination{AddressOrSeed: bob},
+			b.NativeAmount{Amount: "10"},
+		),
+	)
+	if e != nil {
+		t.Fatal(e)
+	}
+	env, e := txn.Sign()
+	if e != nil {
+		t.Fatal(e)
+	}
+	encoded, e := env.Base64()
+	if e != nil {
+		t.Fatal(e)
+	}
+	return encoded
+}
+
+// chain nests a tx request depth times around the innermost request
+func chain(xdr string, innermost string, depth int) string {
+	s := innermost
+	for i := 0; i < depth; i++ {
+		s = (&URI{Operation: OperationTx, XDR: xdr, Chain: s}).String()
+	}
+	return s
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []string{
+		// the examples of the SEP-7 specification
+		"web+stellar:pay?destination=GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO&amount=120.1234567&memo=skdjfasf&memo_type=MEMO_TEXT&msg=pay%20me%20with%20lumens",
+		"web+stellar:pay?destination=GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO&amount=120.123&asset_code=USD&asset_issuer=GCRCUE2C5TBNIPYHMEP7NK5RWTT2WBSZ75CMARH7GDOHDDCQH3XANFOB&memo=hasysda987fs&memo_type=MEMO_TEXT&callback=url%3Ahttps%3A%2F%2FsomeSigningService.com%2Fhasysda987fs%3Fasset%3DUSD",
+		specRequest + "&signature=" + specSignature,
+		// optional parameters
+		"web+stellar:pay?destination=" + bob,
+		"web+stellar:pay?destination=bob%2Aexample.com&memo=42&memo_type=MEMO_ID&network_passphrase=Test%20SDF%20Network%20%3B%20September%202015",
+	}
+	for _, test := range tests {
+		u, e := Parse(test)
+		if e != nil {
+			t.Errorf("%s: %s", test, e)
+			continue
+		}
+		if got := u.String(); got != test {
+			t.Errorf("got %s, want %s", got, test)
+		}
+	}
+}
+
+func TestRoundTripTx(t *testing.T) {
+	xdr := txXDR(t)
+	pay := "web+stellar:pay?destination=" + bob
+	tests := []string{
+		"web+stellar:tx?xdr=" + escape(xdr),
+		"web+stellar:tx?xdr=" + escape(xdr) + "&replace=sourceAccount%3AX%2Coperations%5B0%5D.destination%3AY%3BX%3Aaccount%20to%20pay%20from%2CY%3Aaccount%20to%20pay&callback=url%3Ahttps%3A%2F%2Fexample.com%2Fcallback&pubkey=" + specAddress,
+		"web+stellar:tx?xdr=" + escape(xdr) + "&chain=" + escape(pay),
+	}
+	for _, test := range tests {
+		u, e := Parse(test)
+		if e != nil {
+			t.Errorf("%s: %s", test, e)
+			continue
+		}
+		if got := u.String(); got != test {
+			t.Errorf("got %s, want %s", got, test)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	xdr := txXDR(t)
+	pay := "web+stellar:pay?destination=" + bob
+	tests := []struct {
+		name string
+		in   string
+		err  string
+	}{
+		{"other scheme", "stellar:pay?destination=" + bob, "not a SEP-7 URI request"},
+		{"unknown operation", "web+stellar:sign?xdr=" + escape(xdr), "unknown operation"},
+		{"unknown parameter", pay + "&fee=100", "unknown parameter"},
+		{"repeated parameter", pay + "&amount=1&amount=2", "more than once"},
+		{"bad replace field", "web+stellar:tx?xdr=" + escape(xdr) + "&replace=sourceAccount", "invalid replace field"},
+		{"bad replace hint", "web+stellar:tx?xdr=" + escape(xdr) + "&replace=sourceAccount%3AX%3BX", "invalid replace hint"},
+		{"bad chain", "web+stellar:tx?xdr=" + escape(xdr) + "&chain=" + escape("web+stellar:pay?amount=1"), "invalid chain"},
+		{"chain that is not a request", "web+stellar:tx?xdr=" + escape(xdr) + "&chain=https%3A%2F%2Fexample.com", "invalid chain"},
+		{"chain too deep", chain(xdr, pay, MaxChainDepth+1), "nested more than"},
+		{"bad memo_type", pay + "&memo=42&memo_type=MEMO_NUMBER", "unknown memo_type"},
+		{"memo_type without memo", pay + "&memo_type=MEMO_TEXT", "needs a memo"},
+		{"bad memo id", pay + "&memo=forty-two&memo_type=MEMO_ID", "not a 64 bit unsigned integer"},
+		{"long text memo", pay + "&memo=" + strings.Repeat("x", 29), "longer than"},
+		{"zero amount", pay + "&amount=0", "greater than 0"},
+		{"asset code without issuer", pay + "&asset_code=USD", "given together"},
+		{"bad destination", "web+stellar:pay?destination=GABC", "neither an account ID"},
+		{"bad xdr", "web+stellar:tx?xdr=AAAAA", "invalid xdr"},
+		{"tx parameter in a pay request", pay + "&pubkey=" + specAddress, "unknown parameter 'pubkey'"},
+		{"http callback", pay + "&callback=url%3Ahttp%3A%2F%2Fexample.com%2Fcallback", "not an https URL"},
+		{"callback without prefix", pay + "&callback=https%3A%2F%2Fexample.com", "unsupported callback"},
+		{"long msg", pay + "&msg=" + strings.Repeat("x", MaxMsgLength+1), "characters long"},
+		{"origin_domain without signature", pay + "&origin_domain=example.com", "needs a signature"},
+		{"signature not last", specRequest + "&signature=" + specSignature + "&network_passphrase=Test", "must be the last"},
+	}
+	for _, test := range tests {
+		_, e := Parse(test.in)
+		if e == nil || !strings.Contains(e.Error(), test.err) {
+			t.Errorf("%s: got error %v, want %q", test.name, e, test.err)
+		}
+	}
+}
+
+func TestChainDepth(t *testing.T) {
+	xdr := txXDR(t)
+	pay := "web+stellar:pay?destination=" + bob
+
+	u, e := Parse(chain(xdr, pay, MaxChainDepth))
+	if e != nil {
+		t.Fatalf("a chain nested %d levels deep: %s", MaxChainDepth, e)
+	}
+	if u.Chain == "" {
+		t.Error("the chain was dropped")
+	}
+
+	_, e = Parse(chain(xdr, pay, MaxChainDepth+1))
+	if e == nil {
+		t.Errorf("a chain nested %d levels deep should be rejected", MaxChainDepth+1)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	list, e := parseReplace("sourceAccount:X,operations[0].destination:Y,operations[1].destination:Y;X:account to pay from,Y:account to pay")
+	if e != nil {
+		t.Fatal(e)
+	}
+	want := []Replacement{
+		{Path: "sourceAccount", ID: "X", Hint: "account to pay from"},
+		{Path: "operations[0].destination", ID: "Y", Hint: "account to pay"},
+		{Path: "operations[1].destination", ID: "Y", Hint: "account to pay"},
+	}
+	if len(list) != len(want) {
+		t.Fatalf("got %+v, want %+v", list, want)
+	}
+	for i := range want {
+		if list[i] != want[i] {
+			t.Errorf("entry %d: got %+v, want %+v", i, list[i], want[i])
+		}
+	}
+	if got := formatReplace(list); got != "sourceAccount:X,operations[0].destination:Y,operations[1].destination:Y;X:account to pay from,Y:account to pay" {
+		t.Errorf("formatted as %s", got)
+	}
+}
@@ -0,0 +1,36 @@
+// Package sequence provides sequence number providers for the transaction builder.
+package sequence
+
+import (
+	"fmt"
+	"io"
+
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/xdr"
+)
+
+// OffsetProvider loads the current sequence number of an account from an inner provider and adds an offset to it, which
+// is used to build transactions that will be submitted after others that are not yet on the network
+type OffsetProvider struct {
+	Inner  b.SequenceProvider
+	Offset int64
+	// Log, if set, receives a line describing the adjustment
+	Log io.Writer
+}
+
+var _ b.SequenceProvider = OffsetProvider{}
+
+// SequenceForAccount adds the offset to the result of the inner call
+func (s OffsetProvider) SequenceForAccount(aid string) (xdr.SequenceNumber, error) {
+	seq, e := s.Inner.SequenceForAccount(aid)
+	if e != nil {
+		return seq, e
+	}
+
+	offsetSeq := xdr.SequenceNumber(int64(seq) + s.Offset)
+	if s.Log != nil {
+		// generated XDR will have a seq number of 1 more than this since this is fetching the current seq number only
+		fmt.Fprintf(s.Log, "added offset of %d to convert current fetched sequence number from %d to %d\n", s.Offset, int64(seq), int64(offsetSeq))
+	}
+	return offsetSeq, nil
+}
@@ -0,0 +1,64 @@
+package sequence
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+)
+
+// fixedProvider returns the same sequence number, or error, for every account
+type fixedProvider struct {
+	seq xdr.SequenceNumber
+	err error
+}
+
+func (p fixedProvider) SequenceForAccount(aid string) (xdr.SequenceNumber, error) {
+	return p.seq, p.err
+}
+
+func TestOffsetProvider(t *testing.T) {
+	tests := []struct {
+		name   string
+		inner  fixedProvider
+		offset int64
+		want   xdr.SequenceNumber
+		err    bool
+	}{
+		{name: "no offset", inner: fixedProvider{seq: 100}, offset: 0, want: 100},
+		{name: "offset", inner: fixedProvider{seq: 100}, offset: 3, want: 103},
+		{name: "large sequence", inner: fixedProvider{seq: 81604378624}, offset: 1, want: 81604378625},
+		{name: "inner error", inner: fixedProvider{err: fmt.Errorf("account not found")}, offset: 3, err: true},
+	}
+	for _, test := range tests {
+		var log bytes.Buffer
+		p := OffsetProvider{Inner: test.inner, Offset: test.offset, Log: &log}
+		got, e := p.SequenceForAccount("GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO")
+		if test.err {
+			if e == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			if log.Len() != 0 {
+				t.Errorf("%s: logged %q for a failed lookup", test.name, log.String())
+			}
+			continue
+		}
+		if e != nil {
+			t.Errorf("%s: %s", test.name, e)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: got %d, want %d", test.name, got, test.want)
+		}
+		if want := fmt.Sprintf("from %d to %d", int64(test.inner.seq), int64(test.want)); !bytes.Contains(log.Bytes(), []byte(want)) {
+			t.Errorf("%s: logged %q, want it to mention %q", test.name, log.String(), want)
+		}
+	}
+
+	// the log is optional
+	got, e := OffsetProvider{Inner: fixedProvider{seq: 7}, Offset: 1}.SequenceForAccount("")
+	if e != nil || got != 8 {
+		t.Errorf("without a log: got %d, %v", got, e)
+	}
+}
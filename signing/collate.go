@@ -0,0 +1,79 @@
+/*
+Copyright 2018 Lightyear.io
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signing
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/envelope"
+)
+
+// CollateCmd is a sample reference implementation to collate signatures using multiple signed transactions for a
+// multi-signature coordination service
+var CollateCmd = &cli.Command{
+	Name:    "collate",
+	Summary: "combine the signatures of several signed copies of the same transaction, read from stdin",
+	Run:     runCollate,
+}
+
+func runCollate(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+
+	xdrList := []string{}
+	prompt := "enter the first signed base64-encoded transaction xdr:\n"
+	for {
+		tx, e := ctx.ReadLine(prompt)
+		// the end of a piped list of transactions ends it like an empty line
+		if e == io.EOF {
+			break
+		}
+		if e != nil {
+			return e
+		}
+		tx = strings.TrimSpace(tx)
+		if len(tx) == 0 {
+			fmt.Fprintf(ctx.Stderr, "received empty tx xdr, done entering transactions.\n")
+			break
+		}
+
+		xdrList = append(xdrList, tx)
+		prompt = "\nenter the next signed base64-encoded transaction xdr (enter to continue):\n"
+	}
+	if len(xdrList) == 0 {
+		return fmt.Errorf("no transactions entered")
+	}
+
+	combinedTx, e := envelope.Collate(xdrList)
+	if e != nil {
+		return e
+	}
+	return ctx.Emit(collateResult{Envelope: combinedTx}, func(w io.Writer) {
+		fmt.Fprintf(w, "\n\ncollated transaction:\n%s\n", combinedTx)
+	})
+}
+
+// collateResult is the JSON output of the collate command
+type collateResult struct {
+	Envelope string `json:"envelope"`
+}
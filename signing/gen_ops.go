@@ -0,0 +1,359 @@
+package signing
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsaraf/stellar-go/asset"
+	"github.com/nikhilsaraf/stellar-go/federation"
+	"github.com/nikhilsaraf/stellar-go/price"
+	"github.com/nikhilsaraf/stellar-go/stellartoml"
+	"github.com/nikhilsaraf/stellar-go/stroops"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/strkey"
+)
+
+// maxDataLength is the longest name and value of a data entry in bytes
+const maxDataLength = 64
+
+// operationKeys lists the arguments accepted by each kind of -op
+var operationKeys = map[string][]string{
+	"pay":     {"destination", "amount", "asset"},
+	"trust":   {"asset", "limit"},
+	"offer":   {"selling", "buying", "amount", "price", "id", "passive"},
+	"options": {"inflation-dest", "home-domain", "master-weight", "thresholds", "signer", "set-flags", "clear-flags"},
+	"data":    {"name", "value"},
+}
+
+// accountFlags are the names of the account flags that an options operation can set or clear
+var accountFlags = map[string][2]interface{}{
+	"auth-required":  {b.SetAuthRequired(), b.ClearAuthRequired()},
+	"auth-revocable": {b.SetAuthRevocable(), b.ClearAuthRevocable()},
+	"auth-immutable": {b.SetAuthImmutable(), b.ClearAuthImmutable()},
+}
+
+// stringList is a flag that can be given several times
+type stringList []string
+
+// String implements flag.Value
+func (l *stringList) String() string {
+	return strings.Join(*l, " ")
+}
+
+// Set implements flag.Value
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// operation is an operation built from a -op flag, destination is set for payments
+type operation struct {
+	mutator     b.TransactionMutator
+	description string
+	destination *federation.Record
+}
+
+// parseOperation builds the operation described by spec, the kind of operation followed by key=value arguments such
+// as "trust asset=USD:GABC... limit=1000". Federation addresses are resolved with client.
+func parseOperation(client stellartoml.HTTP, spec string) (*operation, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty operation, expected one of %s", strings.Join(operationKinds(), ", "))
+	}
+	kind := fields[0]
+	keys, ok := operationKeys[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown operation '%s', expected one of %s", kind, strings.Join(operationKinds(), ", "))
+	}
+	args := map[string]string{}
+	for _, f := range fields[1:] {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid argument '%s' of the %s operation, expected key=value", f, kind)
+		}
+		if !contains(keys, kv[0]) {
+			return nil, fmt.Errorf("unknown argument '%s' of the %s operation, expected %s", kv[0], kind, strings.Join(keys, ", "))
+		}
+		if _, ok := args[kv[0]]; ok {
+			return nil, fmt.Errorf("the argument %s of the %s operation is given more than once", kv[0], kind)
+		}
+		args[kv[0]] = kv[1]
+	}
+
+	var op *operation
+	var e error
+	switch kind {
+	case "pay":
+		op, e = payOperation(client, args)
+	case "trust":
+		op, e = trustOperation(args)
+	case "offer":
+		op, e = offerOperation(args)
+	case "options":
+		op, e = optionsOperation(args)
+	case "data":
+		op, e = dataOperation(args)
+	}
+	if e != nil {
+		return nil, fmt.Errorf("invalid %s operation: %s", kind, e)
+	}
+	return op, nil
+}
+
+func operationKinds() []string {
+	kinds := make([]string, 0, len(operationKeys))
+	for k := range operationKeys {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+func payOperation(client stellartoml.HTTP, args map[string]string) (*operation, error) {
+	if args["destination"] == "" || args["amount"] == "" {
+		return nil, fmt.Errorf("destination and amount are required")
+	}
+	amount, e := stroops.Parse(args["amount"])
+	if e != nil {
+		return nil, e
+	}
+	if amount == 0 {
+		return nil, fmt.Errorf("the amount must be greater than 0")
+	}
+	payAsset := asset.Native
+	if args["asset"] != "" {
+		payAsset, e = asset.Parse(args["asset"])
+		if e != nil {
+			return nil, e
+		}
+	}
+	destination, e := federation.Resolve(client, args["destination"])
+	if e != nil {
+		return nil, e
+	}
+	e = checkAccountID(destination.AccountID)
+	if e != nil {
+		return nil, e
+	}
+	return &operation{
+		mutator:     b.Payment(b.Destination{AddressOrSeed: destination.AccountID}, payAsset.Amount(amount.String())),
+		description: fmt.Sprintf("pay %s %s to %s", amount, payAsset, destination),
+		destination: destination,
+	}, nil
+}
+
+func trustOperation(args map[string]string) (*operation, error) {
+	trusted, e := asset.Parse(args["asset"])
+	if e != nil {
+		return nil, e
+	}
+	if trusted.IsNative() {
+		return nil, fmt.Errorf("accounts always hold lumens, a trust line is only needed for issued assets")
+	}
+	if args["limit"] == "" {
+		return &operation{
+			mutator:     b.Trust(trusted.Code, trusted.Issuer),
+			description: fmt.Sprintf("trust %s", trusted),
+		}, nil
+	}
+	limit, e := stroops.Parse(args["limit"])
+	if e != nil {
+		return nil, e
+	}
+	if limit == 0 {
+		return &operation{
+			mutator:     b.RemoveTrust(trusted.Code, trusted.Issuer),
+			description: fmt.Sprintf("remove the trust line for %s", trusted),
+		}, nil
+	}
+	return &operation{
+		mutator:     b.Trust(trusted.Code, trusted.Issuer, b.Limit(limit.String())),
+		description: fmt.Sprintf("trust %s up to %s", trusted, limit),
+	}, nil
+}
+
+func offerOperation(args map[string]string) (*operation, error) {
+	if args["selling"] == "" || args["buying"] == "" || args["amount"] == "" || args["price"] == "" {
+		return nil, fmt.Errorf("selling, buying, amount and price are required")
+	}
+	selling, e := asset.Parse(args["selling"])
+	if e != nil {
+		return nil, fmt.Errorf("invalid selling asset: %s", e)
+	}
+	buying, e := asset.Parse(args["buying"])
+	if e != nil {
+		return nil, fmt.Errorf("invalid buying asset: %s", e)
+	}
+	amount, e := stroops.Parse(args["amount"])
+	if e != nil {
+		return nil, e
+	}
+	offerPrice, e := price.Parse(args["price"])
+	if e != nil {
+		return nil, e
+	}
+	offerID := uint64(0)
+	if args["id"] != "" {
+		offerID, e = strconv.ParseUint(args["id"], 10, 64)
+		if e != nil {
+			return nil, fmt.Errorf("the id '%s' is not an offer ID", args["id"])
+		}
+	}
+	passive := false
+	if args["passive"] != "" {
+		passive, e = strconv.ParseBool(args["passive"])
+		if e != nil {
+			return nil, fmt.Errorf("passive must be true or false")
+		}
+	}
+	// the amount is checked before the id, a passive offer with amount=0 is a bad amount rather than an update
+	if amount == 0 && passive {
+		return nil, fmt.Errorf("the amount of a passive offer must be greater than 0, passive offers are deleted like other offers with id and amount=0")
+	}
+	if amount == 0 && offerID == 0 {
+		return nil, fmt.Errorf("cannot delete a new offer, set id to the ID of the offer to delete")
+	}
+	if passive && offerID != 0 {
+		return nil, fmt.Errorf("passive offers cannot be updated by id")
+	}
+
+	rate := b.Rate{Selling: selling.Build(), Buying: buying.Build(), Price: b.Price(offerPrice.Fraction())}
+	what := fmt.Sprintf("%s %s for %s at %s", amount, selling, buying, offerPrice)
+	switch {
+	case amount == 0:
+		return &operation{mutator: b.DeleteOffer(rate, b.OfferID(offerID)), description: fmt.Sprintf("delete offer %d", offerID)}, nil
+	case passive:
+		return &operation{mutator: b.CreatePassiveOffer(rate, b.Amount(amount.String())), description: "passive offer to sell " + what}, nil
+	case offerID != 0:
+		return &operation{mutator: b.UpdateOffer(rate, b.Amount(amount.String()), b.OfferID(offerID)), description: fmt.Sprintf("update offer %d to sell %s", offerID, what)}, nil
+	}
+	return &operation{mutator: b.CreateOffer(rate, b.Amount(amount.String())), description: "offer to sell " + what}, nil
+}
+
+func optionsOperation(args map[string]string) (*operation, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("at least one of %s is required", strings.Join(operationKeys["options"], ", "))
+	}
+	var muts []interface{}
+	var changes []string
+	if v, ok := args["inflation-dest"]; ok {
+		e := checkAccountID(v)
+		if e != nil {
+			return nil, e
+		}
+		muts = append(muts, b.InflationDest(v))
+		changes = append(changes, "inflation destination "+v)
+	}
+	if v, ok := args["home-domain"]; ok {
+		if len(v) > 32 {
+			return nil, fmt.Errorf("the home domain '%s' is longer than 32 characters", v)
+		}
+		muts = append(muts, b.HomeDomain(v))
+		changes = append(changes, "home domain "+v)
+	}
+	if v, ok := args["master-weight"]; ok {
+		w, e := parseWeight(v)
+		if e != nil {
+			return nil, e
+		}
+		muts = append(muts, b.MasterWeight(w))
+		changes = append(changes, "master weight "+v)
+	}
+	if v, ok := args["thresholds"]; ok {
+		parts := strings.Split(v, "/")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("the thresholds '%s' must be given as low/medium/high", v)
+		}
+		var t [3]uint32
+		for i, part := range parts {
+			w, e := parseWeight(part)
+			if e != nil {
+				return nil, e
+			}
+			t[i] = w
+		}
+		muts = append(muts, b.SetThresholds(t[0], t[1], t[2]))
+		changes = append(changes, "thresholds "+v)
+	}
+	if v, ok := args["signer"]; ok {
+		kv := strings.SplitN(v, "/", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("the signer '%s' must be given as address/weight", v)
+		}
+		e := checkAccountID(kv[0])
+		if e != nil {
+			return nil, e
+		}
+		w, e := parseWeight(kv[1])
+		if e != nil {
+			return nil, e
+		}
+		if w == 0 {
+			muts = append(muts, b.RemoveSigner(kv[0]))
+			changes = append(changes, "remove signer "+kv[0])
+		} else {
+			muts = append(muts, b.AddSigner(kv[0], w))
+			changes = append(changes, fmt.Sprintf("signer %s with weight %d", kv[0], w))
+		}
+	}
+	for i, key := range []string{"set-flags", "clear-flags"} {
+		v, ok := args[key]
+		if !ok {
+			continue
+		}
+		for _, name := range strings.Split(v, ",") {
+			flag, ok := accountFlags[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown account flag '%s', expected auth-required, auth-revocable or auth-immutable", name)
+			}
+			muts = append(muts, flag[i])
+		}
+		changes = append(changes, strings.Replace(key, "-", " ", 1)+" "+v)
+	}
+	return &operation{mutator: b.SetOptions(muts...), description: "set " + strings.Join(changes, ", ")}, nil
+}
+
+func dataOperation(args map[string]string) (*operation, error) {
+	name := args["name"]
+	if name == "" || len(name) > maxDataLength {
+		return nil, fmt.Errorf("the name is required and can be at most %d bytes long", maxDataLength)
+	}
+	value, ok := args["value"]
+	if !ok {
+		return &operation{mutator: b.ClearData(name), description: "clear data entry " + name}, nil
+	}
+	// an empty value= is most likely a mistake, the entry is only removed by leaving out the value
+	if value == "" || len(value) > maxDataLength {
+		return nil, fmt.Errorf("the value must be 1 to %d bytes long, leave it out to remove the entry", maxDataLength)
+	}
+	return &operation{mutator: b.SetData(name, []byte(value)), description: fmt.Sprintf("set data entry %s to '%s'", name, value)}, nil
+}
+
+// parseWeight parses a signer weight or threshold, which ranges from 0 to 255
+func parseWeight(s string) (uint32, error) {
+	w, e := strconv.ParseUint(s, 10, 8)
+	if e != nil {
+		return 0, fmt.Errorf("the weight '%s' must be a number from 0 to 255", s)
+	}
+	return uint32(w), nil
+}
+
+// checkAccountID returns an error when s is not an account ID
+func checkAccountID(s string) error {
+	_, e := strkey.Decode(strkey.VersionByteAccountID, s)
+	if e != nil {
+		return fmt.Errorf("'%s' is not a valid address, expected 56 characters starting with G with a correct checksum", s)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
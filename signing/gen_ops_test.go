@@ -0,0 +1,80 @@
+package signing
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDataOperation(t *testing.T) {
+	tests := []struct {
+		args map[string]string
+		want string
+		err  string
+	}{
+		{args: map[string]string{"name": "config", "value": "on"}, want: "set data entry config to 'on'"},
+		{args: map[string]string{"name": "config"}, want: "clear data entry config"},
+		{args: map[string]string{"name": "config", "value": ""}, err: "leave it out to remove the entry"},
+		{args: map[string]string{"name": "config", "value": strings.Repeat("x", maxDataLength+1)}, err: "bytes long"},
+		{args: map[string]string{"value": "on"}, err: "the name is required"},
+	}
+	for _, test := range tests {
+		op, e := dataOperation(test.args)
+		if test.err != "" {
+			if e == nil || !strings.Contains(e.Error(), test.err) {
+				t.Errorf("%v: got error %v, want %q", test.args, e, test.err)
+			}
+			continue
+		}
+		if e != nil {
+			t.Errorf("%v: %s", test.args, e)
+			continue
+		}
+		if op.description != test.want {
+			t.Errorf("%v: got %q, want %q", test.args, op.description, test.want)
+		}
+	}
+}
+
+func TestOfferOperation(t *testing.T) {
+	offer := func(extra ...string) map[string]string {
+		args := map[string]string{"selling": "native", "buying": "USD:GCALNQQBXAPZ2WIRSDDBMSTAKCUH5SG6U76YBFLQLIXJTF7FE5AX7AOO", "amount": "10", "price": "2"}
+		for i := 0; i+1 < len(extra); i += 2 {
+			args[extra[i]] = extra[i+1]
+		}
+		return args
+	}
+	tests := []struct {
+		name string
+		args map[string]string
+		want string
+		err  string
+	}{
+		{name: "new", args: offer(), want: "offer to sell"},
+		{name: "passive", args: offer("passive", "true"), want: "passive offer to sell"},
+		{name: "update", args: offer("id", "12"), want: "update offer 12"},
+		{name: "delete", args: offer("id", "12", "amount", "0"), want: "delete offer 12"},
+		{name: "delete new", args: offer("amount", "0"), err: "cannot delete a new offer"},
+		{name: "passive without amount", args: offer("passive", "true", "amount", "0"), err: "amount of a passive offer"},
+		{name: "passive delete by id", args: offer("passive", "true", "amount", "0", "id", "12"), err: "amount of a passive offer"},
+		{name: "passive update by id", args: offer("passive", "true", "id", "12"), err: "cannot be updated by id"},
+		{name: "bad id", args: offer("id", "twelve"), err: "not an offer ID"},
+		{name: "bad passive", args: offer("passive", "maybe"), err: "true or false"},
+		{name: "missing price", args: offer("price", ""), err: "are required"},
+	}
+	for _, test := range tests {
+		op, e := offerOperation(test.args)
+		if test.err != "" {
+			if e == nil || !strings.Contains(e.Error(), test.err) {
+				t.Errorf("%s: got error %v, want %q", test.name, e, test.err)
+			}
+			continue
+		}
+		if e != nil {
+			t.Errorf("%s: %s", test.name, e)
+			continue
+		}
+		if !strings.HasPrefix(op.description, test.want) {
+			t.Errorf("%s: got %q, want it to start with %q", test.name, op.description, test.want)
+		}
+	}
+}
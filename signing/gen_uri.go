@@ -0,0 +1,266 @@
+/*
+Copyright 2018 Lightyear.io
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signing
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nikhilsaraf/stellar-go/asset"
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/federation"
+	"github.com/nikhilsaraf/stellar-go/secret"
+	"github.com/nikhilsaraf/stellar-go/sep7"
+	"github.com/nikhilsaraf/stellar-go/stroops"
+	"github.com/nikhilsaraf/stellar-go/timebounds"
+	b "github.com/stellar/go/build"
+	kp "github.com/stellar/go/keypair"
+)
+
+// GenURICmd generates a SEP-7 tx URI request for a transaction that a wallet can fill in, sign and submit
+var GenURICmd = &cli.Command{
+	Name:    "gen",
+	Summary: "generate a SEP-7 tx URI request for a payment or any other operations",
+	Usage: "[-toAddress <address|name*domain> -amount <amount> [-asset <asset>]] [-op <operation>]... [-memo <text>] [-source <address>] " +
+		"[-replace <path>:<hint>]... [-pubkey <address>] [-callback <url>] [-msg <text>] [-origin-domain <domain> [-secret <source>]] " +
+		"[-valid-for <duration>] [-min-time <time>] [-max-time <time>]",
+	Run: runGenURI,
+}
+
+// maxOperations is the largest number of operations in a transaction
+const maxOperations = 100
+
+func runGenURI(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	toAddressPtr := fs.String("toAddress", "", "(optional) destination of a payment, an address or a federation address such as bob*example.com")
+	amountPtr := stroops.Flag(fs, "amount", "(optional) amount of the payment to -toAddress with up to 7 decimal places, must be > 0")
+	assetPtr := fs.String("asset", asset.NativeCode, "(optional) asset of the payment to -toAddress: native, XLM, CODE:ISSUER or CODE-ISSUER")
+	var opSpecs stringList
+	fs.Var(&opSpecs, "op", "(optional, repeatable) operation to include: 'pay destination=<address> amount=<amount> [asset=<asset>]', "+
+		"'trust asset=<asset> [limit=<amount>]', 'offer selling=<asset> buying=<asset> amount=<amount> price=<price> [id=<offer id>] [passive=true]', "+
+		"'options [inflation-dest=<address>] [home-domain=<domain>] [master-weight=<n>] [thresholds=<low>/<medium>/<high>] [signer=<address>/<weight>] "+
+		"[set-flags=<flag>,...] [clear-flags=<flag>,...]' or 'data name=<name> [value=<value>]'")
+	memoPtr := fs.String("memo", "", "(optional) text memo of the transaction")
+	sourcePtr := fs.String("source", "", "(optional) source account of the transaction, left for the wallet to fill in when not given")
+	var replaceSpecs stringList
+	fs.Var(&replaceSpecs, "replace", "(optional, repeatable) field the wallet must fill in, as <path>:<hint> with the path in Txrep notation such as operations[0].destination")
+	pubkeyPtr := fs.String("pubkey", "", "(optional) address that must sign the transaction")
+	callbackPtr := fs.String("callback", "", "(optional) https URL the wallet must post the signed transaction to instead of submitting it")
+	msgPtr := fs.String("msg", "", "(optional) message shown to the user by the wallet")
+	originDomainPtr := fs.String("origin-domain", "", "(optional) domain sending the request, the request is then signed with -secret")
+	secretPtr := secret.Flag(fs, "secret", "URI_REQUEST_SIGNING_KEY of -origin-domain")
+	bounds := timebounds.Flags(fs)
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	if (*toAddressPtr == "") != !stroops.IsSet(fs, "amount") {
+		return cli.UsageErrorf("the -toAddress and -amount flags must be given together")
+	}
+	if *toAddressPtr == "" && len(opSpecs) == 0 {
+		return cli.UsageErrorf("give a payment with -toAddress and -amount, or operations with -op")
+	}
+	if n := len(opSpecs); n > maxOperations || (*toAddressPtr != "" && n == maxOperations) {
+		return cli.UsageErrorf("a transaction can have at most %d operations", maxOperations)
+	}
+	if *toAddressPtr != "" && *amountPtr <= 0 {
+		return cli.UsageErrorf("the -amount must be greater than 0")
+	}
+	_, e = asset.Parse(*assetPtr)
+	if e != nil {
+		return cli.UsageErrorf("invalid -asset: %s", e)
+	}
+	if *sourcePtr != "" && checkAccountID(*sourcePtr) != nil {
+		return cli.UsageErrorf("invalid -source: %s", checkAccountID(*sourcePtr))
+	}
+	if *pubkeyPtr != "" && checkAccountID(*pubkeyPtr) != nil {
+		return cli.UsageErrorf("invalid -pubkey: %s", checkAccountID(*pubkeyPtr))
+	}
+	secretSet := false
+	fs.Visit(func(f *flag.Flag) {
+		secretSet = secretSet || f.Name == "secret"
+	})
+	if secretSet && *originDomainPtr == "" {
+		return cli.UsageErrorf("the -secret flag signs the request on behalf of -origin-domain, which is missing")
+	}
+	replace, e := replacements(replaceSpecs, *sourcePtr == "")
+	if e != nil {
+		return cli.UsageErrorf("invalid -replace: %s", e)
+	}
+	e = bounds.Validate()
+	if e != nil {
+		return cli.UsageErrorf("%s", e)
+	}
+
+	p, e := ctx.Profile()
+	if e != nil {
+		return e
+	}
+
+	// 1. build the operations, the payment given with -toAddress comes first
+	if *toAddressPtr != "" {
+		opSpecs = append(stringList{fmt.Sprintf("pay destination=%s amount=%s asset=%s", *toAddressPtr, amountPtr, *assetPtr)}, opSpecs...)
+	}
+	var ops []*operation
+	for i, spec := range opSpecs {
+		op, e := parseOperation(ctx.HTTPClient(), spec)
+		if e != nil {
+			return e
+		}
+		fmt.Fprintf(ctx.Stderr, "operation %d: %s\n", i, op.description)
+		ops = append(ops, op)
+	}
+	memo, e := transactionMemo(*memoPtr, ops)
+	if e != nil {
+		return e
+	}
+
+	// 2. build the partial transaction, without a sequence number since the wallet fills it in
+	source := *sourcePtr
+	if source == "" {
+		// since the address is the empty sentinel value, the wallet will need to fill it in along with the sequence number
+		source = kp.Master("").Address()
+	}
+	muts := []b.TransactionMutator{
+		b.SourceAccount{AddressOrSeed: source},
+		p.Network(),
+		b.BaseFee{Amount: p.BaseFee},
+	}
+	for _, op := range ops {
+		muts = append(muts, op.mutator)
+	}
+	if memo != nil {
+		muts = append(muts, memo)
+	}
+	boundsMuts, e := bounds.Mutators(time.Now())
+	if e != nil {
+		return e
+	}
+	txn, e := b.Transaction(append(muts, boundsMuts...)...)
+	if e != nil {
+		return e
+	}
+
+	// 3. sign with empty signature so it gets converted to a transaction envelope
+	txnE, e := txn.Sign()
+	if e != nil {
+		return fmt.Errorf("failed to sign: %s", e)
+	}
+
+	// 4. convert to base64
+	txnB64, e := txnE.Base64()
+	if e != nil {
+		return fmt.Errorf("failed to convert to base64: %s", e)
+	}
+
+	// 5. build the URI request, wallets assume the public network unless the passphrase is given
+	request := &sep7.URI{
+		Operation:    sep7.OperationTx,
+		XDR:          txnB64,
+		Replace:      replace,
+		Pubkey:       *pubkeyPtr,
+		Callback:     *callbackPtr,
+		Msg:          *msgPtr,
+		OriginDomain: *originDomainPtr,
+	}
+	if !p.IsPublic() {
+		request.NetworkPassphrase = p.Passphrase
+	}
+	e = request.Validate()
+	if e != nil {
+		return fmt.Errorf("invalid URI request: %s", e)
+	}
+
+	// 6. sign the request on behalf of the origin domain
+	uri := request.String()
+	if request.OriginDomain != "" {
+		signer, e := secret.LoadKeypair(*secretPtr, ctx)
+		if e != nil {
+			return e
+		}
+		uri, e = request.Sign(signer.Seed())
+		if e != nil {
+			return e
+		}
+		fmt.Fprintf(ctx.Stderr, "signed with %s, which %s must publish as URI_REQUEST_SIGNING_KEY in its stellar.toml\n",
+			signer.Address(), request.OriginDomain)
+	}
+	return ctx.Emit(uriResult{URI: uri, Signature: url.QueryEscape(request.Signature)}, func(w io.Writer) {
+		fmt.Fprintln(w, uri)
+	})
+}
+
+// transactionMemo returns the memo of the transaction: the -memo text, or the memo the federation server of a payment's
+// destination requires. Several required memos must all be the same.
+func transactionMemo(text string, ops []*operation) (b.TransactionMutator, error) {
+	var memo b.TransactionMutator
+	if text != "" {
+		memo = b.MemoText{Value: text}
+	}
+	var requiredBy *federation.Record
+	for _, op := range ops {
+		d := op.destination
+		if d == nil || d.MemoType == "" || d.MemoType == "none" {
+			continue
+		}
+		if requiredBy != nil && (d.MemoType != requiredBy.MemoType || d.Memo != requiredBy.Memo) {
+			return nil, fmt.Errorf("%s and %s require different memos, pay them in separate transactions", requiredBy, d)
+		}
+		m, e := d.MemoMutator(text)
+		if e != nil {
+			return nil, e
+		}
+		memo, requiredBy = m, d
+	}
+	return memo, nil
+}
+
+// replacements builds the replace parameter from the -replace flags, identifying the fields with letters. The source
+// account is added when the wallet has to fill it in and the flags do not mention it.
+func replacements(specs []string, emptySource bool) ([]sep7.Replacement, error) {
+	var list []sep7.Replacement
+	hasSource := false
+	for _, spec := range specs {
+		kv := strings.SplitN(spec, ":", 2)
+		if len(kv) != 2 || kv[0] == "" || strings.ContainsAny(spec, ",;") {
+			return nil, fmt.Errorf("'%s' is not <path>:<hint>, neither can contain , or ;", spec)
+		}
+		hasSource = hasSource || kv[0] == "sourceAccount"
+		list = append(list, sep7.Replacement{Path: kv[0], Hint: kv[1]})
+	}
+	if emptySource && !hasSource {
+		list = append([]sep7.Replacement{{Path: "sourceAccount", Hint: "account to sign with"}}, list...)
+	}
+	if len(list) > 26 {
+		return nil, fmt.Errorf("at most 26 fields can be replaced")
+	}
+	for i := range list {
+		list[i].ID = string(rune('A' + i))
+	}
+	return list, nil
+}
+
+// uriResult is the JSON output of the commands that produce a SEP-7 URI request, Signature is the url-encoded
+// signature of a signed request
+type uriResult struct {
+	URI       string `json:"uri"`
+	Signature string `json:"signature,omitempty"`
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2018 Lightyear.io
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signing
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/envelope"
+	"github.com/nikhilsaraf/stellar-go/profile"
+	"github.com/nikhilsaraf/stellar-go/secret"
+	"github.com/nikhilsaraf/stellar-go/sep7"
+	"github.com/nikhilsaraf/stellar-go/stroops"
+	"github.com/nikhilsaraf/stellar-go/submit"
+	b "github.com/stellar/go/build"
+	kp "github.com/stellar/go/keypair"
+)
+
+var emptyAddress = kp.Master("").Address()
+
+// HandleURICmd signs the transaction in a SEP-7 tx URI request, or the payment a pay URI request asks for, and posts it
+// to the request's callback or submits it to the network
+var HandleURICmd = &cli.Command{
+	Name:    "handle",
+	Summary: "sign the transaction or payment in a SEP-7 URI request and submit it or post it to its callback",
+	Usage:   "[-secret <source>] -uri <uri> [-amount <amount>] [-yes] [-require-signed] [-dry-run | -unsigned -sequence <n>] [-out <file>]",
+	Run:     runHandleURI,
+}
+
+func runHandleURI(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	// assumes that the signing account uses only the master key to sign transactions
+	secretPtr := secret.Flag(fs, "secret", "secret key to sign the transaction")
+	uriPtr := fs.String("uri", "", "SEP-7 tx or pay URI request to be signed and submitted")
+	amountPtr := stroops.Flag(fs, "amount", "(optional) amount to pay when a pay request leaves it to the payer")
+	yesPtr := fs.Bool("yes", false, "(optional) sign the transaction or payment without asking for confirmation")
+	requireSignedPtr := fs.Bool("require-signed", false, "(optional) refuse URI requests whose signature cannot be verified against their origin_domain")
+	opts := submit.Flags(fs)
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	if *uriPtr == "" {
+		return cli.UsageErrorf("the -uri flag is required")
+	}
+	e = opts.Validate()
+	if e != nil {
+		return e
+	}
+
+	p, e := ctx.Profile()
+	if e != nil {
+		return e
+	}
+	// 1. parse and validate the URI request, before asking for the secret key of a request that is refused anyway
+	request, e := sep7.Parse(*uriPtr)
+	if e != nil {
+		return e
+	}
+	if request.Passphrase() != p.Passphrase {
+		return fmt.Errorf("the URI request is for the network '%s' but the network profile %s uses '%s', select the right one with --network",
+			request.Passphrase(), p.Name, p.Passphrase)
+	}
+	origin, e := checkOrigin(ctx, opts, request, *requireSignedPtr)
+	if e != nil {
+		return e
+	}
+	// an empty source account is filled in with the signer below, other replacements are not supported
+	ignored := request.Chain != ""
+	for _, r := range request.Replace {
+		ignored = ignored || r.Path != "sourceAccount"
+	}
+	if ignored {
+		fmt.Fprintln(ctx.Stderr, "warning: ignoring the chain and replace parameters of the URI request")
+	}
+	// the requester wants the signed transaction back, for example to collect more signatures, instead of having it
+	// submitted
+	opts.Callback = request.Callback
+
+	signerAddress, signer, e := opts.Signer(*secretPtr, ctx)
+	if e != nil {
+		return e
+	}
+
+	// 2. build the transaction the request asks for
+	horizonClient := p.Client()
+	var txn *b.TransactionEnvelopeBuilder
+	if request.Operation == sep7.OperationPay {
+		txn, e = buildPayment(ctx, opts, p, request, origin, signerAddress, amountPtr, stroops.IsSet(fs, "amount"), *yesPtr)
+	} else {
+		txn, e = buildTx(ctx, opts, p, request, origin, signerAddress, *yesPtr)
+	}
+	if e != nil {
+		return e
+	}
+
+	// 3. sign the transaction envelope and post it to the callback or submit it to the network
+	_, e = opts.Finish(ctx, txn, signer, horizonClient)
+	return e
+}
+
+// checkOrigin verifies the signature of the request against the URI_REQUEST_SIGNING_KEY of its origin_domain and
+// refuses a request whose signature does not match. Requests that cannot be verified, because they are unsigned, have
+// no origin_domain or the command runs offline, are flagged with a warning, or refused when requireSigned is set. It
+// returns the origin to show to the user.
+func checkOrigin(ctx *cli.Context, opts *submit.Options, request *sep7.URI, requireSigned bool) (string, error) {
+	var problem string
+	switch {
+	case request.Signature == "":
+		problem = "the URI request is not signed"
+	case request.OriginDomain == "":
+		problem = "the URI request is signed but has no origin_domain to verify the signature against"
+	case opts.Offline():
+		problem = "the signature of the URI request cannot be verified without network access"
+	default:
+		key, e := request.VerifyOrigin(ctx.HTTPClient())
+		if e != nil {
+			return "", fmt.Errorf("refusing the URI request claiming to come from %s: %s", request.OriginDomain, e)
+		}
+		return fmt.Sprintf("%s (signature verified with %s)", request.OriginDomain, key), nil
+	}
+
+	if requireSigned {
+		return "", fmt.Errorf("refusing the URI request because of -require-signed: %s", problem)
+	}
+	fmt.Fprintf(ctx.Stderr, "warning: %s, nothing proves who created it\n", problem)
+	if request.OriginDomain != "" {
+		return request.OriginDomain + " (signature NOT verified)", nil
+	}
+	return "unknown (unsigned request)", nil
+}
+
+// buildTx prepares the transaction of a tx request for the signer, after showing what it does and asking for
+// confirmation unless yes is set or the transaction is not submitted
+func buildTx(ctx *cli.Context, opts *submit.Options, p *profile.Profile, request *sep7.URI, origin string, signerAddress string, yes bool) (*b.TransactionEnvelopeBuilder, error) {
+	if request.Pubkey != "" && request.Pubkey != signerAddress {
+		return nil, fmt.Errorf("the URI request must be signed by %s, not %s", request.Pubkey, signerAddress)
+	}
+
+	// decode the base64 XDR
+	txn, e := envelope.Decode(request.XDR)
+	if e != nil {
+		return nil, e
+	}
+
+	// check the source account and mutate the transaction inside the transaction envelope:
+	//     a. set the network passphrase, it is not part of the envelope so it is always needed to sign for the profile
+	//     b. update the source account if it is the empty sentinel
+	//     c. set the sequence number and the base fee if the wallet is to fill them in
+	//     d. set the base fee if it was asked for with the fee flags
+	horizonClient := p.Client()
+	muts := []b.TransactionMutator{p.Network()}
+	emptySource := txn.E.Tx.SourceAccount.Address() == emptyAddress
+	if emptySource {
+		// we assume that the accountID uses the master key, this can also be the accountID
+		muts = append(muts, &b.SourceAccount{AddressOrSeed: signerAddress})
+	}
+	fill := emptySource || txn.E.Tx.SeqNum == 0
+	if fill {
+		muts = append(muts, opts.SequenceMutator(horizonClient))
+	}
+	// a retry may only replace the sequence number and signatures when they were filled in here, a complete
+	// transaction is the one the requester asked for and may carry their signatures
+	opts.Built = fill
+	if fill || opts.FeeRequested() {
+		fee, e := opts.FeeMutator(p, horizonClient)
+		if e != nil {
+			return nil, e
+		}
+		muts = append(muts, fee)
+	}
+	e = txn.MutateTX(muts...)
+	if e != nil {
+		return nil, e
+	}
+
+	if opts.Bounds.Set() {
+		bounds, e := opts.Bounds.Mutators(time.Now())
+		if e != nil {
+			return nil, e
+		}
+		e = txn.MutateTX(bounds...)
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	// the summary shows the transaction as it will be signed, with the fields filled in above
+	d, e := envelope.Describe(txn.E)
+	if e != nil {
+		return nil, e
+	}
+	writeTxSummary(ctx.Info(), d, request, origin)
+	if !yes && !opts.DryRun && !opts.Unsigned {
+		prompt := "sign and submit this transaction? [y/N] "
+		if request.Callback != "" {
+			prompt = fmt.Sprintf("sign this transaction and post it to %s? [y/N] ", callbackHost(request.Callback))
+		}
+		e := confirm(ctx, prompt, "transaction")
+		if e != nil {
+			return nil, e
+		}
+	}
+	return txn, nil
+}
+
+// writeTxSummary prints who sent the tx request and what its transaction does so it can be confirmed
+func writeTxSummary(w io.Writer, d *envelope.Description, request *sep7.URI, origin string) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "transaction request:")
+	fmt.Fprintln(w, "  origin:", origin)
+	if request.Msg != "" {
+		fmt.Fprintln(w, "  message:", request.Msg)
+	}
+	if request.Callback != "" {
+		fmt.Fprintln(w, "  callback:", callbackHost(request.Callback), "(the signed transaction is posted there instead of submitted)")
+	}
+	fmt.Fprintln(w)
+	d.WriteText(w)
+	fmt.Fprintln(w)
+}
+
+// callbackHost returns the host a callback posts to, the callback was validated when the request was parsed
+func callbackHost(callback string) string {
+	u, e := url.Parse(callback)
+	if e != nil {
+		return callback
+	}
+	return u.Host
+}
@@ -0,0 +1,199 @@
+package signing_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/horizontest"
+	"github.com/nikhilsaraf/stellar-go/sep7"
+	"github.com/nikhilsaraf/stellar-go/signing"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+)
+
+var root = &cli.Command{Name: "stellar", Subcommands: []*cli.Command{signing.GenURICmd, signing.HandleURICmd}}
+
+// genURI runs the gen command with JSON output and returns the URI request it prints
+func genURI(t *testing.T, s *horizontest.Server, stdin string, args ...string) string {
+	r := s.Run(root, stdin, append([]string{"--output", "json", "gen"}, args...)...)
+	if r.Code != 0 {
+		t.Fatalf("gen: exit %d: %s", r.Code, r.Stderr)
+	}
+	var result struct {
+		URI string `json:"uri"`
+	}
+	e := json.Unmarshal([]byte(r.Stdout), &result)
+	if e != nil {
+		t.Fatalf("%s: %s", e, r.Stdout)
+	}
+	return result.URI
+}
+
+func TestHandleTxCallback(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	domain, _ := keypair.Random()
+	payer, _ := keypair.Random()
+	dest, _ := keypair.Random()
+	s.CreateAccount(payer.Address(), "100")
+	s.CreateAccount(dest.Address(), "100")
+	s.SetURIRequestSigningKey(domain.Address())
+
+	uri := genURI(t, s, domain.Seed()+"\n", "-toAddress", dest.Address(), "-amount", "3",
+		"-callback", "https://example.com/callback", "-origin-domain", "example.com", "-secret", "stdin")
+	seq := s.Sequence(payer.Address())
+	r := s.Run(root, payer.Seed()+"\n", "handle", "-secret", "stdin", "-uri", uri, "-yes", "-require-signed")
+	if r.Code != 0 {
+		t.Fatalf("exit %d: %s", r.Code, r.Stderr)
+	}
+	callbacks := s.Callbacks()
+	if len(callbacks) != 1 {
+		t.Fatalf("got %d callbacks, want 1", len(callbacks))
+	}
+	// the source account and sequence number are left to the wallet
+	s.CheckPayment(t, callbacks[0], payer.Address(), seq, dest.Address(), "3.0000000")
+	if txs := s.Transactions(); len(txs) != 0 {
+		t.Errorf("got %d transactions, the envelope must only be posted to the callback", len(txs))
+	}
+}
+
+func TestHandleTxConfirm(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	payer, _ := keypair.Random()
+	dest, _ := keypair.Random()
+	s.CreateAccount(payer.Address(), "100")
+	s.CreateAccount(dest.Address(), "100")
+
+	uri := genURI(t, s, "", "-toAddress", dest.Address(), "-amount", "4", "-callback", "https://example.com/callback")
+	tests := []struct {
+		name      string
+		answer    string
+		code      int
+		stderr    string
+		callbacks int
+	}{
+		{"no answer", "", 1, "not confirmed, use -yes", 0},
+		{"declined", "n\n", 1, "transaction cancelled", 0},
+		{"confirmed", "y\n", 0, "post it to example.com?", 1},
+	}
+	for _, test := range tests {
+		r := s.Run(root, payer.Seed()+"\n"+test.answer, "handle", "-secret", "stdin", "-uri", uri)
+		if r.Code != test.code {
+			t.Errorf("%s: exit %d, want %d: %s", test.name, r.Code, test.code, r.Stderr)
+		}
+		if !strings.Contains(r.Stderr, test.stderr) {
+			t.Errorf("%s: stderr %q, want it to contain %q", test.name, r.Stderr, test.stderr)
+		}
+		// the transaction and where it goes are shown before asking
+		if !strings.Contains(r.Stdout, dest.Address()) || !strings.Contains(r.Stdout, "callback: example.com") {
+			t.Errorf("%s: the summary does not show the payment and the callback host: %s", test.name, r.Stdout)
+		}
+		if n := len(s.Callbacks()); n != test.callbacks {
+			t.Errorf("%s: got %d callbacks, want %d", test.name, n, test.callbacks)
+		}
+	}
+}
+
+func TestHandleTxSubmit(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	payer, _ := keypair.Random()
+	dest, _ := keypair.Random()
+	s.CreateAccount(payer.Address(), "100")
+	s.CreateAccount(dest.Address(), "100")
+
+	uri := genURI(t, s, "", "-toAddress", dest.Address(), "-amount", "2.5")
+	seq := s.Sequence(payer.Address())
+	r := s.Run(root, payer.Seed()+"\n", "handle", "-secret", "stdin", "-uri", uri, "-yes")
+	if r.Code != 0 {
+		t.Fatalf("exit %d: %s", r.Code, r.Stderr)
+	}
+	txs := s.Transactions()
+	if len(txs) != 1 {
+		t.Fatalf("got %d transactions, want 1", len(txs))
+	}
+	s.CheckPayment(t, txs[0].Env, payer.Address(), seq, dest.Address(), "2.5000000")
+}
+
+func TestHandlePay(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	payer, _ := keypair.Random()
+	dest, _ := keypair.Random()
+	s.CreateAccount(payer.Address(), "100")
+	s.CreateAccount(dest.Address(), "100")
+
+	request := &sep7.URI{
+		Operation:         sep7.OperationPay,
+		Destination:       dest.Address(),
+		Amount:            "7",
+		Memo:              "order 12",
+		MemoType:          sep7.MemoText,
+		NetworkPassphrase: network.TestNetworkPassphrase,
+	}
+	seq := s.Sequence(payer.Address())
+	r := s.Run(root, payer.Seed()+"\n", "handle", "-secret", "stdin", "-uri", request.String(), "-yes")
+	if r.Code != 0 {
+		t.Fatalf("exit %d: %s", r.Code, r.Stderr)
+	}
+	txs := s.Transactions()
+	if len(txs) != 1 {
+		t.Fatalf("got %d transactions, want 1", len(txs))
+	}
+	d := s.CheckPayment(t, txs[0].Env, payer.Address(), seq, dest.Address(), "7.0000000")
+	if d.Memo.Type != "text" || d.Memo.Value != "order 12" {
+		t.Errorf("memo %+v, want the text order 12", d.Memo)
+	}
+}
+
+func TestHandleRefused(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	domain, _ := keypair.Random()
+	other, _ := keypair.Random()
+	payer, _ := keypair.Random()
+	dest, _ := keypair.Random()
+	s.CreateAccount(payer.Address(), "100")
+	s.CreateAccount(dest.Address(), "100")
+	s.SetURIRequestSigningKey(other.Address())
+
+	signed := genURI(t, s, domain.Seed()+"\n", "-toAddress", dest.Address(), "-amount", "1",
+		"-origin-domain", "example.com", "-secret", "stdin")
+	unsigned := genURI(t, s, "", "-toAddress", dest.Address(), "-amount", "1")
+	otherSigner := genURI(t, s, "", "-toAddress", dest.Address(), "-amount", "1", "-pubkey", other.Address())
+	public := (&sep7.URI{Operation: sep7.OperationPay, Destination: dest.Address(), Amount: "1"}).String()
+
+	// only a request that passed the checks asks for the secret key, here to find out who is signing
+	tests := []struct {
+		name  string
+		args  []string
+		code  int
+		asked bool
+	}{
+		{"signature mismatch", []string{"-uri", signed}, 1, false},
+		{"unsigned with -require-signed", []string{"-uri", unsigned, "-require-signed"}, 1, false},
+		{"other signer", []string{"-uri", otherSigner}, 1, true},
+		{"other network", []string{"-uri", public}, 1, false},
+		{"not a URI request", []string{"-uri", "https://example.com"}, 1, false},
+		{"missing URI", []string{}, 2, false},
+	}
+	for _, test := range tests {
+		args := append([]string{"handle", "-secret", "prompt", "-yes"}, test.args...)
+		r := s.Run(root, payer.Seed()+"\n", args...)
+		if r.Code != test.code {
+			t.Errorf("%s: exit %d, want %d: %s", test.name, r.Code, test.code, r.Stderr)
+		}
+		if asked := strings.Contains(r.Stderr, "Enter "); asked != test.asked {
+			t.Errorf("%s: asked for the secret key: %t, want %t", test.name, asked, test.asked)
+		}
+	}
+	if txs := s.Transactions(); len(txs) != 0 {
+		t.Errorf("got %d transactions, want none", len(txs))
+	}
+	if callbacks := s.Callbacks(); len(callbacks) != 0 {
+		t.Errorf("got %d callbacks, want none", len(callbacks))
+	}
+}
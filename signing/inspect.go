@@ -0,0 +1,47 @@
+package signing
+
+import (
+	"io"
+	"strings"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/envelope"
+)
+
+// InspectCmd decodes a transaction envelope into a readable view
+var InspectCmd = &cli.Command{
+	Name:    "inspect",
+	Summary: "decode a base64-encoded transaction envelope into a readable view",
+	Usage:   "[-xdr <envelope>]",
+	Run:     runInspect,
+}
+
+func runInspect(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	xdrPtr := fs.String("xdr", "", "(optional) base-64 encoded XDR to inspect, read from stdin if unspecified")
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+
+	encoded := *xdrPtr
+	if encoded == "" {
+		encoded, e = ctx.ReadLine("enter the base64-encoded transaction xdr:\n")
+		if e != nil {
+			return e
+		}
+	}
+
+	txn, e := envelope.Decode(strings.TrimSpace(encoded))
+	if e != nil {
+		return e
+	}
+	d, e := envelope.Describe(txn.E)
+	if e != nil {
+		return e
+	}
+
+	return ctx.Emit(d, func(w io.Writer) {
+		d.WriteText(w)
+	})
+}
@@ -0,0 +1,165 @@
+package signing
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nikhilsaraf/stellar-go/accounts"
+	"github.com/nikhilsaraf/stellar-go/asset"
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/federation"
+	"github.com/nikhilsaraf/stellar-go/profile"
+	"github.com/nikhilsaraf/stellar-go/sep7"
+	"github.com/nikhilsaraf/stellar-go/stroops"
+	"github.com/nikhilsaraf/stellar-go/submit"
+	b "github.com/stellar/go/build"
+)
+
+// buildPayment builds the payment a pay request asks for from the signer's account, after showing a summary and asking
+// for confirmation unless yes is set or the payment is not submitted. amount is used when the request leaves the
+// amount to the payer, origin describes who sent the request.
+func buildPayment(ctx *cli.Context, opts *submit.Options, p *profile.Profile, request *sep7.URI, origin string, signerAddress string, amount *stroops.Amount, amountSet bool, yes bool) (*b.TransactionEnvelopeBuilder, error) {
+	// the amount is either fixed by the request or given by the payer
+	if request.Amount != "" {
+		requested, _ := stroops.Parse(request.Amount)
+		if amountSet && *amount != requested {
+			return nil, cli.UsageErrorf("the URI request asks for %s, -amount %s does not match", requested, *amount)
+		}
+		*amount = requested
+	} else if !amountSet || *amount == 0 {
+		return nil, cli.UsageErrorf("the URI request leaves the amount to the payer, give it with -amount")
+	}
+
+	payAsset := asset.Native
+	if request.AssetCode != "" {
+		var e error
+		payAsset, e = asset.New(request.AssetCode, request.AssetIssuer)
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	// look up the destination, its federation server may require a memo of its own
+	if opts.Offline() && federation.IsAddress(request.Destination) {
+		return nil, cli.UsageErrorf("federation addresses cannot be resolved without network access")
+	}
+	destination, e := federation.Resolve(ctx.HTTPClient(), request.Destination)
+	if e != nil {
+		return nil, e
+	}
+	memo, e := requestMemo(request, destination)
+	if e != nil {
+		return nil, e
+	}
+
+	horizonClient := p.Client()
+	if !opts.Offline() {
+		sourceAccount, e := accounts.Load(ctx, horizonClient, signerAddress, "source")
+		if e != nil {
+			return nil, e
+		}
+		destinationAccount, e := accounts.Load(ctx, horizonClient, destination.AccountID, "destination")
+		if e != nil {
+			return nil, e
+		}
+		// the issuer does not need to trust its own asset
+		if !asset.Has(&sourceAccount, payAsset) && signerAddress != payAsset.Issuer {
+			return nil, fmt.Errorf("source account does not trust asset: %s", payAsset)
+		}
+		if !asset.Has(&destinationAccount, payAsset) && destination.AccountID != payAsset.Issuer {
+			return nil, fmt.Errorf("destination account does not trust asset: %s", payAsset)
+		}
+	}
+
+	writePaymentSummary(ctx.Info(), request, origin, signerAddress, destination, *amount, payAsset)
+	if !yes && !opts.DryRun && !opts.Unsigned {
+		prompt := "sign and submit this payment? [y/N] "
+		if request.Callback != "" {
+			prompt = "sign this payment and post it to the callback? [y/N] "
+		}
+		e := confirm(ctx, prompt, "payment")
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	txn, e := opts.Build(p, horizonClient, signerAddress,
+		b.Payment(
+			b.Destination{AddressOrSeed: destination.AccountID},
+			payAsset.Amount(amount.String()),
+		),
+	)
+	if e != nil {
+		return nil, e
+	}
+	if memo != nil {
+		e = txn.Mutate(memo)
+		if e != nil {
+			return nil, e
+		}
+	}
+	env, e := txn.Sign()
+	if e != nil {
+		return nil, e
+	}
+	return &env, nil
+}
+
+// confirm asks the user to answer the prompt with y or yes, what names the transaction in the errors
+func confirm(ctx *cli.Context, prompt string, what string) error {
+	answer, e := ctx.ReadLine(prompt)
+	if e == io.EOF {
+		return fmt.Errorf("%s not confirmed, use -yes to sign without asking", what)
+	}
+	if e != nil {
+		return e
+	}
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		return fmt.Errorf("%s cancelled", what)
+	}
+	return nil
+}
+
+// requestMemo returns the memo of the request, or the one required by the destination's federation server when the
+// request has none. Both may only be given when they are the same.
+func requestMemo(request *sep7.URI, destination *federation.Record) (b.TransactionMutator, error) {
+	if request.Memo == "" {
+		return destination.MemoMutator("")
+	}
+	if destination.MemoType != "" && destination.MemoType != "none" {
+		memoType := request.MemoType
+		if memoType == "" {
+			memoType = sep7.MemoText
+		}
+		if memoType != "MEMO_"+strings.ToUpper(destination.MemoType) || request.Memo != destination.Memo {
+			return nil, fmt.Errorf("the URI request's memo '%s' conflicts with the %s memo '%s' that %s requires",
+				request.Memo, destination.MemoType, destination.Memo, destination.StellarAddress)
+		}
+	}
+	return request.MemoMutator()
+}
+
+// writePaymentSummary prints what the payment will do so it can be confirmed
+func writePaymentSummary(w io.Writer, request *sep7.URI, origin string, source string, destination *federation.Record, amount stroops.Amount, payAsset asset.Asset) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "payment request:")
+	fmt.Fprintln(w, "  from:", source)
+	fmt.Fprintln(w, "  to:", destination)
+	fmt.Fprintln(w, "  amount:", amount, payAsset)
+	if request.Memo != "" {
+		memoType := request.MemoType
+		if memoType == "" {
+			memoType = sep7.MemoText
+		}
+		fmt.Fprintf(w, "  memo: %s %s\n", memoType, request.Memo)
+	}
+	if request.Msg != "" {
+		fmt.Fprintln(w, "  message:", request.Msg)
+	}
+	fmt.Fprintln(w, "  origin:", origin)
+	if request.Callback != "" {
+		fmt.Fprintln(w, "  callback:", request.Callback, "(the signed transaction is posted there instead of submitted)")
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,115 @@
+// Package signing implements the commands that sign transactions and SEP-7 URI requests.
+package signing
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/envelope"
+	"github.com/nikhilsaraf/stellar-go/secret"
+	"github.com/nikhilsaraf/stellar-go/timebounds"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/network"
+)
+
+// SignCmd adds a signature to a base64-encoded transaction envelope
+var SignCmd = &cli.Command{
+	Name:    "sign",
+	Summary: "sign a base64-encoded transaction envelope",
+	Usage:   "[-secret <source>] -xdr <envelope> [-force]",
+	Run:     runSign,
+}
+
+func runSign(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	xdrPtr := fs.String("xdr", "", "base-64 encoded XDR to be signed")
+	secretPtr := secret.Flag(fs, "secret", "secret key to sign with")
+	forcePtr := fs.Bool("force", false, "sign the transaction even if its time bounds have expired")
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	if *xdrPtr == "" {
+		return cli.UsageErrorf("the -xdr flag is required")
+	}
+
+	p, e := ctx.Profile()
+	if e != nil {
+		return e
+	}
+
+	signer, e := secret.LoadKeypair(*secretPtr, ctx)
+	if e != nil {
+		return e
+	}
+
+	// decode the base64 XDR
+	txn, e := envelope.Decode(*xdrPtr)
+	if e != nil {
+		return e
+	}
+
+	now := time.Now()
+	fmt.Fprintf(ctx.Info(), "time bounds: %s\n", timebounds.Describe(txn.E.Tx.TimeBounds, now))
+	if timebounds.Expired(txn.E.Tx.TimeBounds, now) {
+		if !*forcePtr {
+			return fmt.Errorf("the transaction expired at %s and can no longer be included in a ledger, use -force to sign it anyway",
+				time.Unix(int64(txn.E.Tx.TimeBounds.MaxTime), 0).UTC().Format(time.RFC3339))
+		}
+		fmt.Fprintf(ctx.Stderr, "warning: signing a transaction whose time bounds have expired\n")
+	}
+
+	fmt.Fprintf(ctx.Info(), "setting the network passphrase to '%s'...", p.Passphrase)
+	e = txn.MutateTX(
+		p.Network(),
+	)
+	if e != nil {
+		return e
+	}
+	fmt.Fprintf(ctx.Info(), "done.\n")
+
+	fmt.Fprintf(ctx.Info(), "signing the transaction...")
+	e = txn.Mutate(&b.Sign{Seed: signer.Seed()})
+	if e != nil {
+		return e
+	}
+	fmt.Fprintf(ctx.Info(), "done.\n")
+
+	fmt.Fprintf(ctx.Info(), "converting the signed XDR to base64...")
+	signedBase64Tx, e := txn.Base64()
+	if e != nil {
+		return fmt.Errorf("failed to convert to base64: %s", e)
+	}
+	fmt.Fprintf(ctx.Info(), "done.\n")
+
+	hash, e := network.HashTransaction(&txn.E.Tx, p.Passphrase)
+	if e != nil {
+		return fmt.Errorf("failed to hash transaction: %s", e)
+	}
+	result := signResult{Hash: hex.EncodeToString(hash[:]), Envelope: signedBase64Tx, Signer: signer.Address()}
+	return ctx.Emit(result, func(w io.Writer) {
+		fmt.Fprintf(w, "\noriginal XDR:\n")
+		fmt.Fprintf(w, "%s\n", *xdrPtr)
+
+		fmt.Fprintf(w, "\nsignedBase64Tx:\n")
+		fmt.Fprintf(w, "%s\n", signedBase64Tx)
+
+		fmt.Fprintf(w, "\nurl-encoded signedBase64Tx:\n")
+		urlEncoded := url.QueryEscape(signedBase64Tx)
+		fmt.Fprintf(w, "%s\n", urlEncoded)
+
+		fmt.Fprintf(w, "\nsubmit command:\n")
+		fmt.Fprintf(w, "curl -X POST \"%s/transactions\" -d \"tx=%s\"\n", p.HorizonURL, urlEncoded)
+	})
+}
+
+// signResult is the JSON output of the sign command
+type signResult struct {
+	Hash     string `json:"hash"`
+	Envelope string `json:"envelope"`
+	Signer   string `json:"signer"`
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2018 Lightyear.io
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signing
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/secret"
+	"github.com/nikhilsaraf/stellar-go/sep7"
+	"github.com/stellar/go/strkey"
+)
+
+// SignURICmd signs a SEP-7 URI request on behalf of its origin domain
+var SignURICmd = &cli.Command{
+	Name:    "sign",
+	Summary: "sign a SEP-7 URI request with the URI_REQUEST_SIGNING_KEY of its origin domain",
+	Usage:   "[-secret <source>] [<uri>]",
+	Run:     runSignURI,
+}
+
+// VerifyURICmd checks the signature of a SEP-7 URI request, exiting with an error when it is not valid
+var VerifyURICmd = &cli.Command{
+	Name:    "verify",
+	Summary: "verify the signature of a SEP-7 URI request against a key or its origin domain",
+	Usage:   "[-key <address>] [<uri>]",
+	Run:     runVerifyURI,
+}
+
+// verifyResult is the JSON output of uri verify
+type verifyResult struct {
+	Valid        bool   `json:"valid"`
+	Key          string `json:"key"`
+	OriginDomain string `json:"origin_domain,omitempty"`
+}
+
+func runSignURI(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	secretPtr := secret.Flag(fs, "secret", "secret key to sign the URI request with")
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	// the URI request is read before the secret, so both can come from stdin in that order
+	uri, e := readURI(ctx, fs)
+	if e != nil {
+		return e
+	}
+	request, e := sep7.ParseUnsigned(uri)
+	if e != nil {
+		return e
+	}
+
+	signer, e := secret.LoadKeypair(*secretPtr, ctx)
+	if e != nil {
+		return e
+	}
+	signed, e := request.Sign(signer.Seed())
+	if e != nil {
+		return e
+	}
+	if request.OriginDomain == "" {
+		fmt.Fprintln(ctx.Stderr, "warning: the URI request has no origin_domain, wallets cannot verify its signature")
+	} else {
+		fmt.Fprintf(ctx.Info(), "signed with %s, which %s must publish as URI_REQUEST_SIGNING_KEY in its stellar.toml\n",
+			signer.Address(), request.OriginDomain)
+	}
+
+	return ctx.Emit(uriResult{URI: signed, Signature: url.QueryEscape(request.Signature)}, func(w io.Writer) {
+		fmt.Fprintln(w, signed)
+	})
+}
+
+func runVerifyURI(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	keyPtr := fs.String("key", "", "(optional) address the URI request must be signed by, instead of the URI_REQUEST_SIGNING_KEY of its origin_domain")
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	if *keyPtr != "" {
+		_, e = strkey.Decode(strkey.VersionByteAccountID, *keyPtr)
+		if e != nil {
+			return cli.UsageErrorf("invalid -key, expected an address of 56 characters starting with G with a correct checksum")
+		}
+	}
+	uri, e := readURI(ctx, fs)
+	if e != nil {
+		return e
+	}
+	request, e := sep7.Parse(uri)
+	if e != nil {
+		return e
+	}
+
+	key := *keyPtr
+	if key != "" {
+		e = request.VerifySignature(key)
+	} else {
+		if request.OriginDomain == "" {
+			return cli.UsageErrorf("the URI request has no origin_domain, give the address it must be signed by with -key")
+		}
+		key, e = request.VerifyOrigin(ctx.HTTPClient())
+	}
+	if e != nil {
+		return e
+	}
+
+	return ctx.Emit(verifyResult{Valid: true, Key: key, OriginDomain: request.OriginDomain}, func(w io.Writer) {
+		if request.OriginDomain != "" {
+			fmt.Fprintf(w, "valid signature by %s for %s\n", key, request.OriginDomain)
+		} else {
+			fmt.Fprintf(w, "valid signature by %s\n", key)
+		}
+	})
+}
+
+// readURI returns the URI request given as the only argument, or read from the first line of stdin when there is none
+func readURI(ctx *cli.Context, fs *flag.FlagSet) (string, error) {
+	switch fs.NArg() {
+	case 0:
+		line, e := ctx.ReadLine("")
+		if e == io.EOF {
+			return "", cli.UsageErrorf("give the URI request as an argument or on stdin")
+		}
+		if e != nil {
+			return "", e
+		}
+		return strings.TrimSpace(line), nil
+	case 1:
+		return fs.Arg(0), nil
+	}
+	return "", cli.UsageErrorf("expected a single URI request, got %d arguments", fs.NArg())
+}
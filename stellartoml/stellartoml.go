@@ -0,0 +1,63 @@
+// Package stellartoml fetches the stellar.toml file (SEP-1) that a domain publishes at
+// https://<domain>/.well-known/stellar.toml to describe its Stellar services.
+package stellartoml
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/BurntSushi/toml"
+)
+
+// maxSize is the largest stellar.toml file SEP-1 allows
+const maxSize = 100 * 1024
+
+// HTTP is the part of http.Client used to fetch the file, so that tests can route the requests to a local server
+type HTTP interface {
+	Get(url string) (*http.Response, error)
+}
+
+// Response holds the fields of a stellar.toml file used by the commands
+type Response struct {
+	// FederationServer is the endpoint of the domain's SEP-2 federation server
+	FederationServer string `toml:"FEDERATION_SERVER"`
+	// URIRequestSigningKey is the account ID whose key signs the domain's SEP-7 URI requests
+	URIRequestSigningKey string `toml:"URI_REQUEST_SIGNING_KEY"`
+}
+
+// URL returns the location of the stellar.toml file of the domain
+func URL(domain string) string {
+	return "https://" + domain + "/.well-known/stellar.toml"
+}
+
+// Get fetches and decodes the stellar.toml file of the domain, client may be nil to use http.DefaultClient
+func Get(client HTTP, domain string) (*Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, e := client.Get(URL(domain))
+	if e != nil {
+		return nil, fmt.Errorf("could not fetch the stellar.toml of %s: %s", domain, e)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch the stellar.toml of %s: status %d", domain, resp.StatusCode)
+	}
+
+	data, e := ioutil.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if e != nil {
+		return nil, fmt.Errorf("could not read the stellar.toml of %s: %s", domain, e)
+	}
+	if len(data) > maxSize {
+		return nil, fmt.Errorf("the stellar.toml of %s is larger than %d bytes", domain, maxSize)
+	}
+
+	var result Response
+	_, e = toml.Decode(string(data), &result)
+	if e != nil {
+		return nil, fmt.Errorf("invalid stellar.toml of %s: %s", domain, e)
+	}
+	return &result, nil
+}
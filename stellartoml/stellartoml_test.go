@@ -0,0 +1,108 @@
+package stellartoml_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsaraf/stellar-go/horizontest"
+	"github.com/nikhilsaraf/stellar-go/stellartoml"
+)
+
+const signingKey = "GD7ACHBPHSC5OJMJZZBXA7Z5IAUFTH6E6XVLNBPASDQYJ7LO5UIYBDQW"
+
+// serverClient sends every request to the test server, whatever its host
+type serverClient struct {
+	target *url.URL
+}
+
+func (c serverClient) Get(u string) (*http.Response, error) {
+	parsed, e := url.Parse(u)
+	if e != nil {
+		return nil, e
+	}
+	parsed.Scheme = c.target.Scheme
+	parsed.Host = c.target.Host
+	return http.Get(parsed.String())
+}
+
+// serve starts a server that answers every request with the status and body
+func serve(status int, body string) (*httptest.Server, stellartoml.HTTP) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/stellar.toml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	target, _ := url.Parse(ts.URL)
+	return ts, serverClient{target: target}
+}
+
+func TestURL(t *testing.T) {
+	if got := stellartoml.URL("example.com"); got != "https://example.com/.well-known/stellar.toml" {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestGet(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+
+	toml, e := stellartoml.Get(s.HTTPClient(), "example.com")
+	if e != nil {
+		t.Fatal(e)
+	}
+	if toml.FederationServer != s.URL+"/federation" {
+		t.Errorf("FEDERATION_SERVER is %s, want %s", toml.FederationServer, s.URL+"/federation")
+	}
+	if toml.URIRequestSigningKey != "" {
+		t.Errorf("URI_REQUEST_SIGNING_KEY is %s, want none", toml.URIRequestSigningKey)
+	}
+
+	s.SetURIRequestSigningKey(signingKey)
+	toml, e = stellartoml.Get(s.HTTPClient(), "example.com")
+	if e != nil {
+		t.Fatal(e)
+	}
+	if toml.URIRequestSigningKey != signingKey {
+		t.Errorf("URI_REQUEST_SIGNING_KEY is %s, want %s", toml.URIRequestSigningKey, signingKey)
+	}
+}
+
+func TestGetErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		err    string
+	}{
+		{"not found", http.StatusNotFound, "", "status 404"},
+		{"server error", http.StatusInternalServerError, "", "status 500"},
+		{"invalid toml", http.StatusOK, "FEDERATION_SERVER = ", "invalid stellar.toml"},
+		{"too large", http.StatusOK, "# " + strings.Repeat("x", 100*1024) + "\n", "larger than"},
+	}
+	for _, test := range tests {
+		ts, client := serve(test.status, test.body)
+		_, e := stellartoml.Get(client, "example.com")
+		ts.Close()
+		if e == nil || !strings.Contains(e.Error(), test.err) {
+			t.Errorf("%s: got error %v, want %q", test.name, e, test.err)
+		}
+	}
+
+	// unknown keys are ignored
+	ts, client := serve(http.StatusOK, "VERSION = \"2.0.0\"\nURI_REQUEST_SIGNING_KEY = \""+signingKey+"\"\n[[CURRENCIES]]\ncode = \"USD\"\n")
+	defer ts.Close()
+	toml, e := stellartoml.Get(client, "example.com")
+	if e != nil {
+		t.Fatal(e)
+	}
+	if toml.URIRequestSigningKey != signingKey || toml.FederationServer != "" {
+		t.Errorf("got %+v", toml)
+	}
+}
@@ -0,0 +1,111 @@
+// Package stroops implements exact amounts with the 7 decimal places used by the network, stored as an integer number
+// of stroops so that they are parsed and printed without the rounding of floating point numbers.
+package stroops
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Decimals is the number of decimal places of an amount
+const Decimals = 7
+
+// One is the number of stroops in one unit of an asset
+const One Amount = 10000000
+
+// Max is the largest amount the network can represent
+const Max Amount = math.MaxInt64
+
+// Amount is an amount in stroops
+type Amount int64
+
+// Parse parses a non-negative decimal amount with at most 7 decimal places, e.g. "12", "0.5" or "0.0000001"
+func Parse(s string) (Amount, error) {
+	if s == "" {
+		return 0, fmt.Errorf("the amount is empty")
+	}
+	if strings.HasPrefix(s, "-") {
+		return 0, fmt.Errorf("the amount %s is negative", s)
+	}
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if whole == "" && frac == "" || !digits(whole) || !digits(frac) {
+		return 0, fmt.Errorf("'%s' is not a decimal amount such as 12 or 0.5", s)
+	}
+
+	// trailing zeros do not add precision
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > Decimals {
+		return 0, fmt.Errorf("the amount %s has more than %d decimal places, the smallest amount is %s", s, Decimals, Amount(1))
+	}
+	frac += strings.Repeat("0", Decimals-len(frac))
+
+	f, _ := strconv.ParseInt(frac, 10, 64)
+	w := int64(0)
+	if whole != "" {
+		var e error
+		w, e = strconv.ParseInt(whole, 10, 64)
+		if e != nil {
+			return 0, fmt.Errorf("the amount %s is larger than the maximum of %s", s, Max)
+		}
+	}
+	if w > (int64(Max)-f)/int64(One) {
+		return 0, fmt.Errorf("the amount %s is larger than the maximum of %s", s, Max)
+	}
+	return Amount(w*int64(One) + f), nil
+}
+
+// digits reports whether s only contains the digits 0-9
+func digits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the amount with 7 decimal places, e.g. "12.5000000", as Horizon and the transaction builder do
+func (a Amount) String() string {
+	sign := ""
+	v := uint64(a)
+	if a < 0 {
+		sign = "-"
+		v = uint64(-a)
+	}
+	return fmt.Sprintf("%s%d.%07d", sign, v/uint64(One), v%uint64(One))
+}
+
+// Set implements flag.Value
+func (a *Amount) Set(s string) error {
+	v, e := Parse(s)
+	if e != nil {
+		return e
+	}
+	*a = v
+	return nil
+}
+
+// Flag registers an amount flag on the given flag set
+func Flag(fs *flag.FlagSet, name string, usage string) *Amount {
+	a := new(Amount)
+	fs.Var(a, name, usage)
+	return a
+}
+
+// IsSet reports whether the flag with the given name was passed on the command line, so that an explicit 0 can be
+// told apart from a missing amount
+func IsSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,109 @@
+package stroops
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+		err  string
+	}{
+		{in: "12", want: 12 * One},
+		{in: "0.5", want: One / 2},
+		{in: ".5", want: One / 2},
+		{in: "5.", want: 5 * One},
+		{in: "0.0000001", want: 1},
+		{in: "120.1234567", want: 1201234567},
+		{in: "007.10", want: 71000000},
+		{in: "0", want: 0},
+		{in: "0.000000", want: 0},
+		// trailing zeros past the 7th decimal place add no precision
+		{in: "1.00000000", want: One},
+		{in: "1.00000010", want: One + 1},
+		{in: "922337203685.4775807", want: Max},
+		{in: "0.00000001", err: "more than 7 decimal places"},
+		{in: "1.12345678", err: "more than 7 decimal places"},
+		{in: "922337203685.4775808", err: "larger than the maximum"},
+		{in: "922337203686", err: "larger than the maximum"},
+		{in: "99999999999999999999", err: "larger than the maximum"},
+		{in: "", err: "empty"},
+		{in: "-1", err: "negative"},
+		{in: "-0.5", err: "negative"},
+		{in: "+1", err: "not a decimal amount"},
+		{in: "1e7", err: "not a decimal amount"},
+		{in: "1E-7", err: "not a decimal amount"},
+		{in: ".", err: "not a decimal amount"},
+		{in: "1.2.3", err: "not a decimal amount"},
+		{in: " 1", err: "not a decimal amount"},
+		{in: "1,5", err: "not a decimal amount"},
+		{in: "0x10", err: "not a decimal amount"},
+	}
+	for _, test := range tests {
+		got, e := Parse(test.in)
+		if test.err != "" {
+			if e == nil || !strings.Contains(e.Error(), test.err) {
+				t.Errorf("%q: got %d, %v, want error %q", test.in, got, e, test.err)
+			}
+			continue
+		}
+		if e != nil {
+			t.Errorf("%q: %s", test.in, e)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%q: got %d, want %d", test.in, got, test.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in   Amount
+		want string
+	}{
+		{0, "0.0000000"},
+		{1, "0.0000001"},
+		{One, "1.0000000"},
+		{1201234567, "120.1234567"},
+		{Max, "922337203685.4775807"},
+		{-One / 2, "-0.5000000"},
+	}
+	for _, test := range tests {
+		got := test.in.String()
+		if got != test.want {
+			t.Errorf("%d: got %s, want %s", int64(test.in), got, test.want)
+			continue
+		}
+		// every non-negative amount parses back to itself
+		if test.in < 0 {
+			continue
+		}
+		back, e := Parse(got)
+		if e != nil || back != test.in {
+			t.Errorf("%s parsed back to %d, %v", got, back, e)
+		}
+	}
+}
+
+func TestFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(new(strings.Builder))
+	a := Flag(fs, "amount", "amount to pay")
+	Flag(fs, "limit", "trust line limit")
+	e := fs.Parse([]string{"-amount", "0"})
+	if e != nil {
+		t.Fatal(e)
+	}
+	if *a != 0 || !IsSet(fs, "amount") || IsSet(fs, "limit") {
+		t.Errorf("amount %s, set %t, limit set %t", *a, IsSet(fs, "amount"), IsSet(fs, "limit"))
+	}
+
+	e = fs.Parse([]string{"-amount", "1.5x"})
+	if e == nil {
+		t.Error("expected an invalid amount to be rejected")
+	}
+}
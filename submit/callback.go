@@ -0,0 +1,52 @@
+package submit
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+)
+
+// maxCallbackResponse is the largest part of a callback's response that is kept
+const maxCallbackResponse = 64 * 1024
+
+// deliver posts the signed envelope to the callback as the xdr form field, as SEP-7 specifies, and emits the result
+// with the callback's response. A response other than 2xx is an error, and so is a callback that is not https since
+// the envelope would be sent in the clear.
+func (o *Options) deliver(ctx *cli.Context, result *Result) error {
+	cb, e := url.Parse(o.Callback)
+	if e != nil || cb.Scheme != "https" || cb.Host == "" {
+		return fmt.Errorf("refusing to post the signed transaction to the callback %s, it is not an https URL", o.Callback)
+	}
+	fmt.Fprintf(ctx.Info(), "posting the signed transaction to the callback %s...", o.Callback)
+	resp, e := ctx.HTTPClient().PostForm(o.Callback, url.Values{"xdr": {result.Envelope}})
+	if e != nil {
+		fmt.Fprintln(ctx.Info(), "failed.")
+		return fmt.Errorf("could not reach the callback %s: %s", o.Callback, e)
+	}
+	defer resp.Body.Close()
+	body, e := ioutil.ReadAll(io.LimitReader(resp.Body, maxCallbackResponse))
+	if e != nil {
+		fmt.Fprintln(ctx.Info(), "failed.")
+		return fmt.Errorf("could not read the response of the callback %s: %s", o.Callback, e)
+	}
+	response := strings.TrimSpace(string(body))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		fmt.Fprintln(ctx.Info(), "failed.")
+		return fmt.Errorf("the callback %s rejected the transaction with status %d: %s", o.Callback, resp.StatusCode, response)
+	}
+	fmt.Fprintln(ctx.Info(), "done.")
+
+	result.Callback = o.Callback
+	result.CallbackStatus = resp.StatusCode
+	result.CallbackResponse = response
+	return ctx.Emit(result, func(w io.Writer) {
+		fmt.Fprintf(w, "transaction posted to %s, status %d\n", o.Callback, resp.StatusCode)
+		if response != "" {
+			fmt.Fprintln(w, "response:", response)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package submit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+)
+
+// callbackServer starts an https callback that answers with the status and body and records the xdr it was posted
+func callbackServer(status int, body string, posted *[]string) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*posted = append(*posted, r.PostFormValue("xdr"))
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestDeliver(t *testing.T) {
+	var posted []string
+	ts := callbackServer(http.StatusOK, `{"status":"received"}`+"\n", &posted)
+	defer ts.Close()
+
+	var stdout bytes.Buffer
+	ctx := &cli.Context{Stdout: &stdout, Stderr: &stdout, HTTP: ts.Client(), Output: cli.OutputJSON}
+	o := &Options{Callback: ts.URL + "/callback"}
+	e := o.deliver(ctx, &Result{Envelope: "AAAA", Signed: true})
+	if e != nil {
+		t.Fatal(e)
+	}
+	if len(posted) != 1 || posted[0] != "AAAA" {
+		t.Errorf("posted %q, want the envelope once", posted)
+	}
+
+	// the progress message goes to stderr, here the same buffer, before the JSON document
+	out := stdout.String()
+	var result Result
+	e = json.Unmarshal([]byte(out[strings.Index(out, "{"):]), &result)
+	if e != nil {
+		t.Fatalf("%s: %s", out, e)
+	}
+	if result.Callback != o.Callback || result.CallbackStatus != http.StatusOK || result.CallbackResponse != `{"status":"received"}` {
+		t.Errorf("got %+v", result)
+	}
+}
+
+func TestDeliverErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		url    func(ts *httptest.Server) string
+		posts  int
+		err    string
+	}{
+		{"rejected", http.StatusBadRequest, func(ts *httptest.Server) string { return ts.URL + "/callback" }, 1, "rejected the transaction with status 400: bad envelope"},
+		{"server error", http.StatusInternalServerError, func(ts *httptest.Server) string { return ts.URL + "/callback" }, 1, "status 500"},
+		{"http", http.StatusOK, func(ts *httptest.Server) string { return strings.Replace(ts.URL, "https:", "http:", 1) + "/callback" }, 0, "not an https URL"},
+		{"not a URL", http.StatusOK, func(ts *httptest.Server) string { return "callback" }, 0, "not an https URL"},
+	}
+	for _, test := range tests {
+		var posted []string
+		ts := callbackServer(test.status, "bad envelope", &posted)
+		var stdout bytes.Buffer
+		ctx := &cli.Context{Stdout: &stdout, Stderr: &stdout, HTTP: ts.Client()}
+		o := &Options{Callback: test.url(ts)}
+		e := o.deliver(ctx, &Result{Envelope: "AAAA", Signed: true})
+		ts.Close()
+		if e == nil || !strings.Contains(e.Error(), test.err) {
+			t.Errorf("%s: got error %v, want %q", test.name, e, test.err)
+		}
+		if len(posted) != test.posts {
+			t.Errorf("%s: posted %d times, want %d", test.name, len(posted), test.posts)
+		}
+	}
+}
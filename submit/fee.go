@@ -0,0 +1,145 @@
+package submit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/nikhilsaraf/stellar-go/profile"
+	"github.com/stellar/go/clients/horizon"
+)
+
+// fee strategies selected with -fee-strategy, a percentile strategy is written as p<n>, e.g. p90
+const (
+	// FeeFixed uses -fee, or the profile's base fee
+	FeeFixed = "fixed"
+	// FeeEscalate starts at -fee and doubles the fee after every tx_insufficient_fee until it reaches -max-fee
+	FeeEscalate = "escalate"
+)
+
+// DefaultMaxFee is the highest base fee, in stroops per operation, the fee strategies go up to unless -max-fee is set
+const DefaultMaxFee = 10000
+
+// percentiles are the accepted fee percentiles reported by Horizon's /fee_stats endpoint
+var percentiles = []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 95, 99}
+
+// checkFeeStrategy returns a usage error for unknown strategies
+func checkFeeStrategy(strategy string) error {
+	if strategy == FeeFixed || strategy == FeeEscalate {
+		return nil
+	}
+	if _, ok := percentile(strategy); ok {
+		return nil
+	}
+	return fmt.Errorf("unknown fee strategy '%s', expected %s, %s or a percentile of the recently accepted fees such as p90", strategy, FeeFixed, FeeEscalate)
+}
+
+// percentile returns n for a strategy of the form p<n> when n is one of the percentiles Horizon reports
+func percentile(strategy string) (int, bool) {
+	if !strings.HasPrefix(strategy, "p") {
+		return 0, false
+	}
+	n, e := strconv.Atoi(strategy[1:])
+	if e != nil {
+		return 0, false
+	}
+	for _, p := range percentiles {
+		if p == n {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// baseFee resolves the base fee per operation for the selected strategy
+func (o *Options) baseFee(p *profile.Profile, client *horizon.Client) (uint64, error) {
+	fee := p.BaseFee
+	if o.Fee != 0 {
+		fee = o.Fee
+	}
+	n, ok := percentile(o.FeeStrategy)
+	if !ok || o.Offline() {
+		return fee, nil
+	}
+
+	stats, e := loadFeeStats(client)
+	if e != nil {
+		return 0, e
+	}
+	accepted, e := stats.accepted(n)
+	if e != nil {
+		return 0, e
+	}
+	// never go below -fee or the network's minimum, nor above the cap
+	if accepted > fee {
+		fee = accepted
+	}
+	if stats.minimum() > fee {
+		fee = stats.minimum()
+	}
+	if fee > o.MaxFee {
+		return 0, fmt.Errorf("the p%d fee of %d stroops per operation is above -max-fee %d", n, fee, o.MaxFee)
+	}
+	return fee, nil
+}
+
+// escalate returns the next base fee to try after a tx_insufficient_fee, false when the strategy does not escalate or
+// the cap has been reached
+func (o *Options) escalate(current uint64) (uint64, bool) {
+	if o.FeeStrategy != FeeEscalate || current >= o.MaxFee {
+		return 0, false
+	}
+	next := current * 2
+	if next > o.MaxFee {
+		next = o.MaxFee
+	}
+	return next, true
+}
+
+// feeStats is Horizon's /fee_stats response, keyed by field name
+type feeStats map[string]json.RawMessage
+
+// accepted returns the n-th percentile of the fees accepted in the last ledgers
+func (s feeStats) accepted(n int) (uint64, error) {
+	raw, ok := s[fmt.Sprintf("p%d_accepted_fee", n)]
+	if !ok {
+		return 0, fmt.Errorf("horizon did not report the p%d accepted fee", n)
+	}
+	return parseStat(raw)
+}
+
+// minimum returns the network's base fee, 0 if unknown
+func (s feeStats) minimum() uint64 {
+	fee, _ := parseStat(s["last_ledger_base_fee"])
+	return fee
+}
+
+// parseStat parses a number that Horizon may encode as a string or as a number
+func parseStat(raw json.RawMessage) (uint64, error) {
+	v, e := strconv.ParseUint(strings.Trim(string(raw), `"`), 10, 64)
+	if e != nil {
+		return 0, fmt.Errorf("invalid fee in horizon's fee stats: %s", raw)
+	}
+	return v, nil
+}
+
+// loadFeeStats fetches the recently accepted fees from Horizon
+func loadFeeStats(client *horizon.Client) (feeStats, error) {
+	resp, e := get(client, "/fee_stats")
+	if e != nil {
+		return nil, e
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not load fee stats: horizon returned status %d", resp.StatusCode)
+	}
+
+	stats := feeStats{}
+	e = json.NewDecoder(resp.Body).Decode(&stats)
+	if e != nil {
+		return nil, fmt.Errorf("could not decode fee stats: %s", e)
+	}
+	return stats, nil
+}
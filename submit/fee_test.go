@@ -0,0 +1,120 @@
+package submit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nikhilsaraf/stellar-go/horizontest"
+	"github.com/nikhilsaraf/stellar-go/txerror"
+	"github.com/stellar/go/keypair"
+)
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     int
+		ok       bool
+	}{
+		{"p10", 10, true},
+		{"p90", 90, true},
+		{"p99", 99, true},
+		{"p15", 0, false},
+		{"p100", 0, false},
+		{"p", 0, false},
+		{"90", 0, false},
+		{FeeFixed, 0, false},
+		{FeeEscalate, 0, false},
+	}
+	for _, test := range tests {
+		got, ok := percentile(test.strategy)
+		if got != test.want || ok != test.ok {
+			t.Errorf("%s: got %d, %t, want %d, %t", test.strategy, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestEscalate(t *testing.T) {
+	o := &Options{FeeStrategy: FeeEscalate, MaxFee: 1000}
+	var fees []uint64
+	for fee, ok := uint64(100), true; ok; fee, ok = o.escalate(fee) {
+		fees = append(fees, fee)
+	}
+	want := []uint64{100, 200, 400, 800, 1000}
+	if len(fees) != len(want) {
+		t.Fatalf("escalated through %v, want %v", fees, want)
+	}
+	for i := range want {
+		if fees[i] != want[i] {
+			t.Errorf("escalated through %v, want %v", fees, want)
+			break
+		}
+	}
+
+	for _, strategy := range []string{FeeFixed, "p90"} {
+		o := &Options{FeeStrategy: strategy, MaxFee: 1000}
+		if _, ok := o.escalate(100); ok {
+			t.Errorf("the %s strategy escalated", strategy)
+		}
+	}
+}
+
+func TestFeeStrategies(t *testing.T) {
+	tests := []struct {
+		name  string
+		surge int64
+		args  []string
+		fee   uint32
+		sent  int
+		code  int
+		err   string
+	}{
+		{name: "fixed ignores the fee stats", surge: 300, args: []string{"-fee", "200"}, sent: 1, code: txerror.ExitFee},
+		{name: "fixed", surge: 100, args: []string{"-fee", "200"}, fee: 200, sent: 1},
+		{name: "percentile", surge: 300, args: []string{"-fee-strategy", "p90"}, fee: 300, sent: 1},
+		{name: "percentile below -fee", surge: 300, args: []string{"-fee-strategy", "p50", "-fee", "500"}, fee: 500, sent: 1},
+		{name: "percentile above -max-fee", surge: 300, args: []string{"-fee-strategy", "p90", "-max-fee", "250"}, code: txerror.ExitFailed, err: "above -max-fee 250"},
+		{name: "escalate", surge: 350, args: []string{"-fee-strategy", "escalate", "-fee", "100"}, fee: 400, sent: 3},
+		{name: "escalate stops at -max-fee", surge: 350, args: []string{"-fee-strategy", "escalate", "-fee", "100", "-max-fee", "300"}, sent: 3, code: txerror.ExitFee},
+		{name: "escalate to -max-fee", surge: 300, args: []string{"-fee-strategy", "escalate", "-fee", "100", "-max-fee", "300"}, fee: 300, sent: 3},
+		// an envelope the command did not build keeps its fee and signatures
+		{name: "escalate received envelope", surge: 300, args: []string{"-fee-strategy", "escalate", "-fee", "100", "-received"}, sent: 1, code: txerror.ExitFee},
+	}
+	for _, test := range tests {
+		s := horizontest.NewServer("")
+		payer, _ := keypair.Random()
+		dest, _ := keypair.Random()
+		s.CreateAccount(payer.Address(), "100")
+		s.CreateAccount(dest.Address(), "100")
+		s.SurgePrice(test.surge)
+
+		r, _, sent := pay(s, payer.Seed(), dest.Address(), test.args...)
+		txs := s.Transactions()
+		s.Close()
+		if r.Code != test.code {
+			t.Errorf("%s: exit %d, want %d: %s", test.name, r.Code, test.code, r.Stderr)
+		}
+		if !strings.Contains(r.Stderr, test.err) {
+			t.Errorf("%s: stderr %q, want it to mention %q", test.name, r.Stderr, test.err)
+		}
+		if len(sent) != test.sent {
+			t.Errorf("%s: sent %d envelopes, want %d", test.name, len(sent), test.sent)
+		}
+		if test.code != 0 {
+			if len(txs) != 0 {
+				t.Errorf("%s: got %d transactions, want none", test.name, len(txs))
+			}
+			continue
+		}
+		if len(txs) != 1 {
+			t.Errorf("%s: got %d transactions, want 1", test.name, len(txs))
+			continue
+		}
+		d, e := horizontest.Describe(txs[0].Env)
+		if e != nil {
+			t.Fatal(e)
+		}
+		if d.Fee != test.fee {
+			t.Errorf("%s: fee %d, want %d", test.name, d.Fee, test.fee)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package submit
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/txerror"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+)
+
+// how often and how many times Horizon is asked whether a transaction was included after a failed submission, long
+// enough for a few ledgers to close
+var (
+	pollInterval = 5 * time.Second
+	pollAttempts = 6
+)
+
+// submission tracks the attempts to submit one logical transaction. Every envelope that was sent is remembered so that
+// the transaction is only rebuilt with a new sequence number once none of them were included in a ledger, which
+// guarantees that it is applied at most once.
+type submission struct {
+	ctx        *cli.Context
+	client     *horizon.Client
+	passphrase string
+	kp         *keypair.Full
+	// resign is set when the envelope may be changed and signed again with kp alone, which drops its other signatures
+	resign bool
+	// rebuild is set when the sequence number may be replaced after a tx_bad_seq
+	rebuild bool
+	hashes  []string
+	// timedOut is set once an attempt timed out, until then every envelope sent was definitely rejected
+	timedOut bool
+	// escalate returns the next base fee to try after a tx_insufficient_fee, false to give up
+	escalate func(current uint64) (uint64, bool)
+}
+
+// submit sends the envelope and retries up to retries times: after a timeout the same envelope is resubmitted unless
+// it turns out to have been included, after a tx_bad_seq the envelope is rebuilt with the next sequence number and
+// signed again, so env holds the last envelope that was sent. Raising the fee after a tx_insufficient_fee does not
+// count as a retry, it is bounded by the fee cap instead. Envelopes that may not be signed again are only ever
+// resubmitted unchanged.
+func (s *submission) submit(env *b.TransactionEnvelopeBuilder, retries int) (*horizon.TransactionSuccess, error) {
+	attempt := 0
+	for {
+		txeB64, e := env.Base64()
+		if e != nil {
+			return nil, fmt.Errorf("failed to convert to base64: %s", e)
+		}
+		hash, e := s.hash(env)
+		if e != nil {
+			return nil, e
+		}
+		s.remember(hash)
+
+		fmt.Fprintf(s.ctx.Info(), "tx base64: %s\n", txeB64)
+		resp, e := s.client.SubmitTransaction(txeB64)
+		if e == nil {
+			return &resp, nil
+		}
+		e = txerror.Decode(e)
+		txErr, ok := e.(*txerror.Error)
+		if !ok {
+			return nil, e
+		}
+		if txErr.Transaction.Name == "tx_insufficient_fee" && s.resign {
+			current := uint64(env.E.Tx.Fee) / uint64(len(env.E.Tx.Operations))
+			next, ok := s.escalate(current)
+			if !ok {
+				return nil, e
+			}
+			fmt.Fprintf(s.ctx.Stderr, "fee of %d stroops per operation is too low, raising it to %d\n", current, next)
+			e = s.mutate(env, b.BaseFee{Amount: next})
+			if e != nil {
+				return nil, e
+			}
+			continue
+		}
+		if attempt >= retries {
+			return nil, e
+		}
+		attempt++
+
+		switch {
+		case txErr.Timeout():
+			s.timedOut = true
+			fmt.Fprintf(s.ctx.Stderr, "submission timed out, checking whether transaction %s was included\n", hash)
+			found, e := s.poll()
+			if e != nil || found != nil {
+				return found, e
+			}
+			// the sequence number makes sure the same envelope can only be applied once
+			fmt.Fprintf(s.ctx.Stderr, "transaction was not included, resubmitting it (retry %d of %d)\n", attempt, retries)
+		case txErr.Transaction.Name == "tx_bad_seq" && s.rebuild:
+			// an earlier attempt that timed out may have used up the sequence number
+			if s.timedOut {
+				found, e := s.poll()
+				if e != nil || found != nil {
+					return found, e
+				}
+			}
+			fmt.Fprintf(s.ctx.Stderr, "sequence number is out of date, rebuilding the transaction (retry %d of %d)\n", attempt, retries)
+			e = s.mutate(env, b.AutoSequence{SequenceProvider: s.client})
+			if e != nil {
+				return nil, fmt.Errorf("could not rebuild the transaction: %s", e)
+			}
+		default:
+			return nil, e
+		}
+	}
+}
+
+// hash returns the hex encoded hash of the envelope's transaction
+func (s *submission) hash(env *b.TransactionEnvelopeBuilder) (string, error) {
+	hash, e := network.HashTransaction(&env.E.Tx, s.passphrase)
+	if e != nil {
+		return "", fmt.Errorf("failed to hash transaction: %s", e)
+	}
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func (s *submission) remember(hash string) {
+	for _, h := range s.hashes {
+		if h == hash {
+			return
+		}
+	}
+	s.hashes = append(s.hashes, hash)
+}
+
+// mutate changes the transaction and replaces its signatures with that of kp, it must only be used when resign is set
+func (s *submission) mutate(env *b.TransactionEnvelopeBuilder, muts ...b.TransactionMutator) error {
+	e := env.MutateTX(muts...)
+	if e != nil {
+		return e
+	}
+	env.E.Signatures = nil
+	return env.Mutate(&b.Sign{Seed: s.kp.Seed()})
+}
+
+// poll asks Horizon for every envelope submitted so far until one of them is found or the attempts run out, it returns
+// nil when none of them were included
+func (s *submission) poll() (*horizon.TransactionSuccess, error) {
+	for i := 0; i < pollAttempts; i++ {
+		if i > 0 {
+			time.Sleep(pollInterval)
+		}
+		for _, hash := range s.hashes {
+			found, e := loadTransaction(s.client, hash)
+			if e != nil {
+				return nil, e
+			}
+			if found != nil {
+				fmt.Fprintf(s.ctx.Stderr, "transaction %s was included in ledger %d\n", hash, found.Ledger)
+				return found, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// loadTransaction fetches a transaction by hash, it returns nil when Horizon does not know the transaction
+func loadTransaction(client *horizon.Client, hash string) (*horizon.TransactionSuccess, error) {
+	resp, e := get(client, "/transactions/"+hash)
+	if e != nil {
+		return nil, e
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not look up transaction %s: horizon returned status %d", hash, resp.StatusCode)
+	}
+	var tx horizon.TransactionSuccess
+	e = json.NewDecoder(resp.Body).Decode(&tx)
+	if e != nil {
+		return nil, fmt.Errorf("could not decode transaction %s: %s", hash, e)
+	}
+	return &tx, nil
+}
+
+// get requests an endpoint the Horizon client does not support with the client's HTTP client
+func get(client *horizon.Client, path string) (*http.Response, error) {
+	var httpClient horizon.HTTP = http.DefaultClient
+	if client.HTTP != nil {
+		httpClient = client.HTTP
+	}
+	resp, e := httpClient.Get(strings.TrimRight(client.URL, "/") + path)
+	if e != nil {
+		return nil, txerror.Decode(e)
+	}
+	return resp, nil
+}
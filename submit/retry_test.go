@@ -0,0 +1,221 @@
+package submit
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/horizontest"
+	"github.com/nikhilsaraf/stellar-go/secret"
+	"github.com/nikhilsaraf/stellar-go/txerror"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/keypair"
+)
+
+// payRoot returns a command tree with a pay command that sends 10 lumens from the -secret account to -to with the
+// shared flags. With -stale another transaction uses up the sequence number after the envelope is built, and with
+// -received the envelope is handled like one the command did not build.
+func payRoot(s *horizontest.Server) *cli.Command {
+	pay := &cli.Command{
+		Name: "pay",
+		Run: func(ctx *cli.Context, args []string) error {
+			fs := ctx.FlagSet()
+			secretPtr := secret.Flag(fs, "secret", "secret key of the payer")
+			toPtr := fs.String("to", "", "account to pay")
+			stalePtr := fs.Bool("stale", false, "use up the sequence number before submitting")
+			receivedPtr := fs.Bool("received", false, "handle the envelope like one the command did not build")
+			opts := Flags(fs)
+			e := ctx.Parse(fs, args)
+			if e != nil {
+				return e
+			}
+			e = opts.Validate()
+			if e != nil {
+				return e
+			}
+			p, e := ctx.Profile()
+			if e != nil {
+				return e
+			}
+			address, kp, e := opts.Signer(*secretPtr, ctx)
+			if e != nil {
+				return e
+			}
+			txn, e := opts.Build(p, p.Client(), address, b.Payment(
+				b.Destination{AddressOrSeed: *toPtr},
+				b.NativeAmount{Amount: "10"},
+			))
+			if e != nil {
+				return e
+			}
+			env, e := txn.Sign()
+			if e != nil {
+				return e
+			}
+			if *stalePtr {
+				e = s.BumpSequence(address)
+				if e != nil {
+					return e
+				}
+			}
+			if *receivedPtr {
+				opts.Built = false
+			}
+			_, e = opts.Finish(ctx, &env, kp, p.Client())
+			return e
+		},
+	}
+	return &cli.Command{Name: "stellar", Subcommands: []*cli.Command{pay}}
+}
+
+// pay runs the pay command with JSON output and returns the result, the hash it reports and every envelope it sent
+func pay(s *horizontest.Server, seed string, to string, args ...string) (horizontest.Result, string, []string) {
+	args = append([]string{"--output", "json", "pay", "-secret", "stdin", "-to", to}, args...)
+	r := s.Run(payRoot(s), seed+"\n", args...)
+	var sent []string
+	for _, line := range strings.Split(r.Stderr, "\n") {
+		if strings.HasPrefix(line, "tx base64: ") {
+			sent = append(sent, strings.TrimPrefix(line, "tx base64: "))
+		}
+	}
+	// stdout is empty when the command failed
+	var result Result
+	json.Unmarshal([]byte(r.Stdout), &result)
+	return r, result.Hash, sent
+}
+
+// noPollWait makes Horizon be polled without waiting between attempts, it returns a func that restores the interval
+func noPollWait() func() {
+	interval := pollInterval
+	pollInterval = 0
+	return func() { pollInterval = interval }
+}
+
+func TestSubmitTimeout(t *testing.T) {
+	defer noPollWait()()
+	tests := []struct {
+		name    string
+		setup   func(s *horizontest.Server)
+		args    []string
+		sent    int
+		stderr  string
+		rebuilt bool
+	}{
+		// the poll finds the transaction, the envelope is not sent again
+		{name: "included", setup: func(s *horizontest.Server) { s.TimeOut(1, true) }, sent: 1, stderr: "was included in ledger"},
+		// the same envelope is sent again, its sequence number makes that safe
+		{name: "not included", setup: func(s *horizontest.Server) { s.TimeOut(1, false) }, sent: 2, stderr: "resubmitting it"},
+		// the envelope lands after the poll, so the resubmission gets tx_bad_seq and the second poll finds it instead
+		// of rebuilding the transaction
+		{name: "included late", setup: func(s *horizontest.Server) { s.TimeOutLate(1) }, sent: 2, stderr: "was included in ledger"},
+		// another transaction used the sequence number, nothing that was sent is found so the transaction is rebuilt
+		{name: "bad sequence after timeout", setup: func(s *horizontest.Server) { s.TimeOut(1, false) }, args: []string{"-stale"}, sent: 3, stderr: "rebuilding the transaction", rebuilt: true},
+	}
+	for _, test := range tests {
+		s := horizontest.NewServer("")
+		payer, _ := keypair.Random()
+		dest, _ := keypair.Random()
+		s.CreateAccount(payer.Address(), "100")
+		s.CreateAccount(dest.Address(), "100")
+		test.setup(s)
+
+		seq := s.Sequence(payer.Address())
+		r, hash, sent := pay(s, payer.Seed(), dest.Address(), test.args...)
+		txs := s.Transactions()
+		s.Close()
+		if r.Code != 0 {
+			t.Errorf("%s: exit %d: %s", test.name, r.Code, r.Stderr)
+			continue
+		}
+		if !strings.Contains(r.Stderr, test.stderr) {
+			t.Errorf("%s: stderr %q, want it to mention %q", test.name, r.Stderr, test.stderr)
+		}
+		if len(sent) != test.sent {
+			t.Errorf("%s: sent %d envelopes, want %d", test.name, len(sent), test.sent)
+		}
+		// the payment is applied once whatever happened to the attempts
+		if len(txs) != 1 {
+			t.Errorf("%s: got %d transactions, want 1", test.name, len(txs))
+			continue
+		}
+		if txs[0].Hash != hash {
+			t.Errorf("%s: reported hash %s, the included transaction is %s", test.name, hash, txs[0].Hash)
+		}
+		for i := 1; i < len(sent); i++ {
+			if changed := sent[i] != sent[0]; changed != (test.rebuilt && i == len(sent)-1) {
+				t.Errorf("%s: envelope %d changed: %t", test.name, i+1, changed)
+			}
+		}
+		if test.rebuilt {
+			// the sequence number used up by the other transaction is skipped
+			seq++
+		}
+		s.CheckPayment(t, txs[0].Env, payer.Address(), seq, dest.Address(), "10.0000000")
+	}
+}
+
+func TestSubmitRetryLimit(t *testing.T) {
+	defer noPollWait()()
+	s := horizontest.NewServer("")
+	defer s.Close()
+	payer, _ := keypair.Random()
+	dest, _ := keypair.Random()
+	s.CreateAccount(payer.Address(), "100")
+	s.CreateAccount(dest.Address(), "100")
+	s.TimeOut(5, false)
+
+	r, _, sent := pay(s, payer.Seed(), dest.Address(), "-retries", "2")
+	if r.Code != txerror.ExitUnavailable {
+		t.Errorf("exit %d, want %d: %s", r.Code, txerror.ExitUnavailable, r.Stderr)
+	}
+	if len(sent) != 3 {
+		t.Errorf("sent %d envelopes, want the first attempt and 2 retries", len(sent))
+	}
+	if txs := s.Transactions(); len(txs) != 0 {
+		t.Errorf("got %d transactions, want none", len(txs))
+	}
+
+	// without retries the first timeout is final
+	s.TimeOut(1, false)
+	r, _, sent = pay(s, payer.Seed(), dest.Address(), "-retries", "0")
+	if r.Code != txerror.ExitUnavailable || len(sent) != 1 {
+		t.Errorf("exit %d after sending %d envelopes, want %d after 1", r.Code, len(sent), txerror.ExitUnavailable)
+	}
+}
+
+func TestSubmitNotRebuilt(t *testing.T) {
+	defer noPollWait()()
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"sequence given with -sequence", nil},
+		{"received envelope", []string{"-received"}},
+	}
+	for _, test := range tests {
+		s := horizontest.NewServer("")
+		payer, _ := keypair.Random()
+		dest, _ := keypair.Random()
+		s.CreateAccount(payer.Address(), "100")
+		s.CreateAccount(dest.Address(), "100")
+
+		args := append([]string{"-stale"}, test.args...)
+		if test.args == nil {
+			args = append(args, "-sequence", strconv.FormatInt(s.Sequence(payer.Address())+1, 10))
+		}
+		r, _, sent := pay(s, payer.Seed(), dest.Address(), args...)
+		txs := s.Transactions()
+		s.Close()
+		if r.Code != txerror.ExitBadSequence {
+			t.Errorf("%s: exit %d, want %d: %s", test.name, r.Code, txerror.ExitBadSequence, r.Stderr)
+		}
+		if len(sent) != 1 {
+			t.Errorf("%s: sent %d envelopes, the transaction must not be rebuilt", test.name, len(sent))
+		}
+		if len(txs) != 0 {
+			t.Errorf("%s: got %d transactions, want none", test.name, len(txs))
+		}
+	}
+}
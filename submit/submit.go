@@ -0,0 +1,283 @@
+// Package submit implements the flags and the final step shared by every command that builds, signs and submits a
+// transaction: the envelope is either submitted to Horizon or posted to a SEP-7 callback, or with -dry-run signed and
+// printed without submitting it, or with -unsigned built without a signature and without any network access.
+package submit
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/profile"
+	"github.com/nikhilsaraf/stellar-go/secret"
+	"github.com/nikhilsaraf/stellar-go/timebounds"
+	"github.com/nikhilsaraf/stellar-go/txerror"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+)
+
+// Result is the outcome of Finish and the JSON output of every command that submits a transaction
+type Result struct {
+	// Hash is the hex encoded hash of the transaction on the selected network
+	Hash string `json:"hash"`
+	// Envelope is the base64 encoded transaction envelope
+	Envelope string `json:"envelope"`
+	// Signed is false for -unsigned envelopes
+	Signed bool `json:"signed"`
+	// Submitted is false for -dry-run and -unsigned envelopes
+	Submitted bool `json:"submitted"`
+	// Ledger is the ledger the transaction was included in, when it was submitted
+	Ledger int32 `json:"ledger,omitempty"`
+	// File is the -out file the envelope was written to
+	File string `json:"file,omitempty"`
+	// Callback is the URL the envelope was posted to instead of being submitted
+	Callback string `json:"callback,omitempty"`
+	// CallbackStatus is the HTTP status code of the callback's response
+	CallbackStatus int `json:"callback_status,omitempty"`
+	// CallbackResponse is the body of the callback's response
+	CallbackResponse string `json:"callback_response,omitempty"`
+}
+
+// Options holds the values of the shared flags
+type Options struct {
+	// DryRun builds and signs the transaction but only prints it
+	DryRun bool
+	// Unsigned builds the transaction without signing it, no network access is made so Sequence must be set
+	Unsigned bool
+	// Out is the file the envelope is written to instead of stdout when it is not submitted
+	Out string
+	// Sequence is the sequence number of the transaction, 0 loads the next one from Horizon
+	Sequence uint64
+	// Source is the address of the source account of an unsigned transaction, when no secret key is available
+	Source string
+	// Retries is the number of times a submission that timed out or had a bad sequence number is retried
+	Retries int
+	// Fee is the base fee in stroops per operation, 0 uses the network profile's base fee
+	Fee uint64
+	// FeeStrategy is FeeFixed, FeeEscalate or a percentile of the recently accepted fees such as p90
+	FeeStrategy string
+	// MaxFee caps the base fee chosen by the percentile and escalate strategies
+	MaxFee uint64
+	// Bounds are the time bounds of the transaction
+	Bounds *timebounds.Options
+	// Callback is the URL a SEP-7 request asks the signed envelope to be posted to instead of submitting it, it is not a
+	// flag and is set by the commands that handle such requests
+	Callback string
+	// Built is set when the command built the transaction or filled in its sequence number, so that a retry may raise
+	// its fee or replace its sequence number and sign it again with the command's key alone. Build sets it, commands
+	// that fill in an envelope they received set it themselves. Other envelopes may carry signatures of other keys and
+	// are never changed.
+	Built bool
+}
+
+// Flags registers the shared flags on the flag set
+func Flags(fs *flag.FlagSet) *Options {
+	o := &Options{Bounds: timebounds.Flags(fs)}
+	fs.BoolVar(&o.DryRun, "dry-run", false, "(optional) build and sign the transaction and print the envelope without submitting it")
+	fs.BoolVar(&o.Unsigned, "unsigned", false, "(optional) build the unsigned envelope without any network access, requires -sequence")
+	fs.StringVar(&o.Out, "out", "", "(optional) file to write the envelope to instead of stdout when it is not submitted")
+	fs.Uint64Var(&o.Sequence, "sequence", 0, "(optional) sequence number of the transaction, the account's current sequence number + 1. loaded from the network if unspecified")
+	fs.StringVar(&o.Source, "source", "", "(optional) with -unsigned, the address of the source account instead of reading its secret key")
+	fs.Uint64Var(&o.Fee, "fee", 0, "(optional) base fee in stroops per operation, defaults to the network profile's base fee")
+	fs.StringVar(&o.FeeStrategy, "fee-strategy", FeeFixed, "(optional) fixed: use -fee, p10 to p99: use that percentile of the fees Horizon saw accepted recently, escalate: double the fee on tx_insufficient_fee")
+	fs.Uint64Var(&o.MaxFee, "max-fee", DefaultMaxFee, "(optional) highest base fee in stroops per operation the percentile and escalate strategies may use")
+	fs.IntVar(&o.Retries, "retries", 3, "(optional) number of times to retry a submission that timed out or had a bad sequence number, the transaction is never applied twice")
+	return o
+}
+
+// Validate checks that the flags are consistent, it returns a usage error
+func (o *Options) Validate() error {
+	if o.DryRun && o.Unsigned {
+		return cli.UsageErrorf("-dry-run and -unsigned cannot be used together")
+	}
+	if o.Unsigned && o.Sequence == 0 {
+		return cli.UsageErrorf("-unsigned requires an explicit -sequence since the network is not accessed")
+	}
+	if o.Source != "" && !o.Unsigned {
+		return cli.UsageErrorf("-source can only be used with -unsigned")
+	}
+	if o.Out != "" && !o.DryRun && !o.Unsigned {
+		return cli.UsageErrorf("-out can only be used with -dry-run or -unsigned")
+	}
+	if o.Retries < 0 {
+		return cli.UsageErrorf("-retries cannot be negative")
+	}
+	e := checkFeeStrategy(o.FeeStrategy)
+	if e != nil {
+		return cli.UsageErrorf("%s", e)
+	}
+	if o.FeeStrategy != FeeFixed && o.Fee > o.MaxFee {
+		return cli.UsageErrorf("-fee %d is above -max-fee %d", o.Fee, o.MaxFee)
+	}
+	e = o.Bounds.Validate()
+	if e != nil {
+		return cli.UsageErrorf("%s", e)
+	}
+	return nil
+}
+
+// Offline reports whether the command must not access the network
+func (o *Options) Offline() bool {
+	return o.Unsigned
+}
+
+// Signer returns the address of the source account and the key pair to sign with. The key pair is nil when the
+// transaction is unsigned and the source address was given with -source.
+func (o *Options) Signer(secretSpec string, prompter secret.Prompter) (string, *keypair.Full, error) {
+	if o.Unsigned && o.Source != "" {
+		_, e := keypair.Parse(o.Source)
+		if e != nil {
+			return "", nil, cli.UsageErrorf("invalid -source address: %s", e)
+		}
+		return o.Source, nil, nil
+	}
+	kp, e := secret.LoadKeypair(secretSpec, prompter)
+	if e != nil {
+		return "", nil, e
+	}
+	return kp.Address(), kp, nil
+}
+
+// Build builds a transaction from the source account with the sequence number, network, base fee and time bounds
+// selected by the flags, followed by the given operations and other mutators
+func (o *Options) Build(p *profile.Profile, client *horizon.Client, source string, muts ...b.TransactionMutator) (*b.TransactionBuilder, error) {
+	o.Built = true
+	fee, e := o.baseFee(p, client)
+	if e != nil {
+		return nil, e
+	}
+	bounds, e := o.Bounds.Mutators(time.Now())
+	if e != nil {
+		return nil, e
+	}
+	all := []b.TransactionMutator{
+		b.SourceAccount{AddressOrSeed: source},
+		o.SequenceMutator(client),
+		p.Network(),
+		b.BaseFee{Amount: fee},
+	}
+	all = append(all, bounds...)
+	return b.Transaction(append(all, muts...)...)
+}
+
+// FeeRequested reports whether a base fee or fee strategy was given with the flags, rather than left to the defaults
+func (o *Options) FeeRequested() bool {
+	return o.Fee != 0 || o.FeeStrategy != FeeFixed
+}
+
+// FeeMutator sets the base fee selected by the flags, for transactions that are not created with Build
+func (o *Options) FeeMutator(p *profile.Profile, client *horizon.Client) (b.TransactionMutator, error) {
+	fee, e := o.baseFee(p, client)
+	if e != nil {
+		return nil, e
+	}
+	return b.BaseFee{Amount: fee}, nil
+}
+
+// SequenceMutator sets the sequence number given with -sequence or loads it from the provider
+func (o *Options) SequenceMutator(provider b.SequenceProvider) b.TransactionMutator {
+	if o.Sequence != 0 {
+		return b.Sequence{Sequence: o.Sequence}
+	}
+	return b.AutoSequence{SequenceProvider: provider}
+}
+
+// Finish signs the envelope with the key pair unless it is unsigned, then either writes it out, posts it to the
+// Callback or submits it with the client and emits the Result. Submissions that time out or have a bad sequence number
+// are retried without ever applying the transaction twice. The fee and sequence number are only changed for
+// transactions the command built, see Built, and the sequence number only when it was not given with -sequence. The
+// response is nil when the transaction was not submitted.
+func (o *Options) Finish(ctx *cli.Context, env *b.TransactionEnvelopeBuilder, kp *keypair.Full, client *horizon.Client) (*horizon.TransactionSuccess, error) {
+	p, e := ctx.Profile()
+	if e != nil {
+		return nil, e
+	}
+	if !o.Unsigned {
+		if kp == nil {
+			return nil, fmt.Errorf("no key to sign the transaction with")
+		}
+		e := env.Mutate(&b.Sign{Seed: kp.Seed()})
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	txeB64, e := env.Base64()
+	if e != nil {
+		return nil, fmt.Errorf("failed to convert to base64: %s", e)
+	}
+	hash, e := network.HashTransaction(&env.E.Tx, p.Passphrase)
+	if e != nil {
+		return nil, fmt.Errorf("failed to hash transaction: %s", e)
+	}
+	result := &Result{
+		Hash:     hex.EncodeToString(hash[:]),
+		Envelope: txeB64,
+		Signed:   !o.Unsigned,
+	}
+
+	if o.DryRun || o.Unsigned {
+		return nil, o.write(ctx, result)
+	}
+	if o.Callback != "" {
+		return nil, o.deliver(ctx, result)
+	}
+
+	s := &submission{
+		ctx:        ctx,
+		client:     client,
+		passphrase: p.Passphrase,
+		kp:         kp,
+		resign:     o.Built,
+		rebuild:    o.Built && o.Sequence == 0,
+		escalate:   o.escalate,
+	}
+	resp, e := s.submit(env, o.Retries)
+	if e != nil {
+		if txErr, ok := e.(*txerror.Error); ok {
+			txErr.WriteText(ctx.Stderr)
+		}
+		return nil, e
+	}
+	// a retry may have changed the envelope, or an earlier attempt may be the one that was included
+	if resp.Hash != "" && resp.Env != "" {
+		result.Hash = resp.Hash
+		result.Envelope = resp.Env
+	}
+	result.Submitted = true
+	result.Ledger = resp.Ledger
+	e = ctx.Emit(result, func(w io.Writer) {
+		fmt.Fprintln(w, "transaction posted in ledger:", resp.Ledger)
+	})
+	if e != nil {
+		return nil, e
+	}
+	return resp, nil
+}
+
+// write prints the envelope or writes it to the -out file
+func (o *Options) write(ctx *cli.Context, result *Result) error {
+	what := "signed"
+	if o.Unsigned {
+		what = "unsigned"
+	}
+	if o.Out == "" {
+		return ctx.Emit(result, func(w io.Writer) {
+			fmt.Fprintf(w, "%s envelope (not submitted):\n%s\n", what, result.Envelope)
+		})
+	}
+
+	e := ioutil.WriteFile(o.Out, []byte(result.Envelope+"\n"), 0644)
+	if e != nil {
+		return e
+	}
+	result.File = o.Out
+	fmt.Fprintf(ctx.Stderr, "wrote %s envelope to %s, it was not submitted\n", what, o.Out)
+	return ctx.Emit(result, nil)
+}
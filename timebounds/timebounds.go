@@ -0,0 +1,131 @@
+// Package timebounds implements the flags shared by every command that builds a transaction to limit the time during
+// which it can be included in a ledger, so that a signed envelope does not stay valid forever.
+package timebounds
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/xdr"
+)
+
+// Options holds the values of the time bound flags
+type Options struct {
+	// ValidFor sets the maximum time to now + ValidFor
+	ValidFor time.Duration
+	// MinTime and MaxTime are unix timestamps or RFC 3339 times
+	MinTime string
+	MaxTime string
+}
+
+// Flags registers the time bound flags on the flag set
+func Flags(fs *flag.FlagSet) *Options {
+	o := &Options{}
+	fs.DurationVar(&o.ValidFor, "valid-for", 0, "(optional) how long the transaction stays valid from now, e.g. 10m or 24h")
+	fs.StringVar(&o.MinTime, "min-time", "", "(optional) time before which the transaction is not valid, as a unix timestamp or RFC 3339 time")
+	fs.StringVar(&o.MaxTime, "max-time", "", "(optional) time after which the transaction is not valid, as a unix timestamp or RFC 3339 time")
+	return o
+}
+
+// Validate checks that the flags are consistent
+func (o *Options) Validate() error {
+	if o.ValidFor < 0 {
+		return fmt.Errorf("-valid-for cannot be negative")
+	}
+	if o.ValidFor != 0 && o.MaxTime != "" {
+		return fmt.Errorf("-valid-for and -max-time cannot be used together")
+	}
+	minTime, maxTime, e := o.bounds(time.Now())
+	if e != nil {
+		return e
+	}
+	if maxTime != 0 && minTime > maxTime {
+		return fmt.Errorf("the minimum time is after the maximum time")
+	}
+	return nil
+}
+
+// Set reports whether any time bound was given
+func (o *Options) Set() bool {
+	return o.ValidFor != 0 || o.MinTime != "" || o.MaxTime != ""
+}
+
+// Mutators returns the mutator that sets the time bounds relative to now, or none when no time bound was given
+func (o *Options) Mutators(now time.Time) ([]b.TransactionMutator, error) {
+	if !o.Set() {
+		return nil, nil
+	}
+	minTime, maxTime, e := o.bounds(now)
+	if e != nil {
+		return nil, e
+	}
+	return []b.TransactionMutator{b.Timebounds{MinTime: minTime, MaxTime: maxTime}}, nil
+}
+
+func (o *Options) bounds(now time.Time) (uint64, uint64, error) {
+	minTime, e := parseTime(o.MinTime)
+	if e != nil {
+		return 0, 0, fmt.Errorf("invalid -min-time: %s", e)
+	}
+	maxTime, e := parseTime(o.MaxTime)
+	if e != nil {
+		return 0, 0, fmt.Errorf("invalid -max-time: %s", e)
+	}
+	if o.ValidFor != 0 {
+		maxTime = uint64(now.Add(o.ValidFor).Unix())
+	}
+	return minTime, maxTime, nil
+}
+
+// parseTime parses a unix timestamp or an RFC 3339 time, an empty string is 0
+func parseTime(s string) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if ts, e := strconv.ParseUint(s, 10, 64); e == nil {
+		return ts, nil
+	}
+	t, e := time.Parse(time.RFC3339, s)
+	if e != nil {
+		return 0, fmt.Errorf("'%s' is neither a unix timestamp nor an RFC 3339 time such as 2018-06-01T12:00:00Z", s)
+	}
+	if t.Unix() < 0 {
+		return 0, fmt.Errorf("'%s' is before 1970", s)
+	}
+	return uint64(t.Unix()), nil
+}
+
+// Expired reports whether a transaction with the time bounds can no longer be included in a ledger at now
+func Expired(tb *xdr.TimeBounds, now time.Time) bool {
+	return tb != nil && tb.MaxTime != 0 && uint64(now.Unix()) > uint64(tb.MaxTime)
+}
+
+// Describe formats the time bounds for people, e.g. "valid until 2018-06-01T12:00:00Z (in 9m59s)"
+func Describe(tb *xdr.TimeBounds, now time.Time) string {
+	if tb == nil || (tb.MinTime == 0 && tb.MaxTime == 0) {
+		return "no time bounds, valid forever"
+	}
+	s := ""
+	if tb.MinTime != 0 {
+		s = "valid from " + describeTime(uint64(tb.MinTime), now)
+	}
+	if tb.MaxTime != 0 {
+		if s != "" {
+			s += ", "
+		}
+		s += "valid until " + describeTime(uint64(tb.MaxTime), now)
+	}
+	return s
+}
+
+func describeTime(ts uint64, now time.Time) string {
+	t := time.Unix(int64(ts), 0).UTC()
+	d := t.Sub(now).Round(time.Second)
+	if d < 0 {
+		return fmt.Sprintf("%s (%s ago)", t.Format(time.RFC3339), -d)
+	}
+	return fmt.Sprintf("%s (in %s)", t.Format(time.RFC3339), d)
+}
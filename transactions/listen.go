@@ -0,0 +1,118 @@
+// Package transactions implements the commands that send and stream payments.
+package transactions
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/nikhilsaraf/stellar-go/asset"
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/stellar/go/clients/horizon"
+	"golang.org/x/net/context"
+)
+
+// ListenCmd streams the payments received by an account
+var ListenCmd = &cli.Command{
+	Name:    "listen",
+	Summary: "stream the payments received by an account",
+	Usage:   "-a <address> [-s <cursor>]",
+	Run:     runListen,
+}
+
+// Payment is a received payment in the JSON output of the listen command, one JSON object per line. Asset is "native"
+// for lumens and "code:issuer" otherwise.
+type Payment struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	PagingToken string `json:"paging_token"`
+	Asset       string `json:"asset"`
+	Amount      string `json:"amount"`
+	MemoType    string `json:"memo_type"`
+	Memo        string `json:"memo"`
+}
+
+func bindPaymentHandler(ctx *cli.Context, address string) func(horizon.Payment) {
+	return func(p horizon.Payment) {
+		if p.To != address {
+			return
+		}
+
+		payment := Payment{
+			ID:          p.ID,
+			Type:        p.Type,
+			From:        p.From,
+			To:          p.To,
+			PagingToken: p.PagingToken,
+			Asset:       asset.NativeCode,
+			Amount:      p.Amount,
+			MemoType:    p.Memo.Type,
+			Memo:        p.Memo.Value,
+		}
+		if p.AssetType != "native" {
+			payment.Asset = p.AssetCode + ":" + p.AssetIssuer
+		}
+		e := ctx.EmitLine(payment, func(w io.Writer) {
+			writePayment(w, p)
+		})
+		if e != nil {
+			fmt.Fprintln(ctx.Stderr, "error:", e)
+		}
+	}
+}
+
+func writePayment(w io.Writer, p horizon.Payment) {
+	var assetStr string
+	if p.AssetType == "native" {
+		assetStr = "lumens"
+	} else {
+		assetStr = p.AssetCode + ":" + p.AssetIssuer
+	}
+
+	fmt.Fprintf(w, "\nID=%v"+
+		"\nType=%v"+
+		"\nFrom=%v"+
+		"\nTo=%v"+
+		"\nPagingToken=%v"+
+		"\nAsset=%v"+
+		"\nAmount=%v"+
+		"\nMemoType=%v"+
+		"\nMemo=%v"+
+		"\n",
+		p.ID,
+		p.Type,
+		p.From,
+		p.To,
+		p.PagingToken,
+		assetStr,
+		p.Amount,
+		p.Memo.Type,
+		p.Memo.Value,
+	)
+}
+
+func runListen(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	addressPtr := fs.String("a", "", "the address for which we want to stream payments")
+	sinceTokenPtr := fs.String("s", "", "(optional) token after which we want to stream payments, excludes the token provided")
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	if *addressPtr == "" {
+		return cli.UsageErrorf("the -a flag is required")
+	}
+
+	p, e := ctx.Profile()
+	if e != nil {
+		return e
+	}
+	address := *addressPtr
+	fmt.Fprintln(ctx.Info(), "network:", p)
+	fmt.Fprintln(ctx.Info(), "address entered:", address)
+	fmt.Fprintln(ctx.Info(), "since token:", *sinceTokenPtr)
+
+	cursor := horizon.Cursor(*sinceTokenPtr)
+	return p.Client().StreamPayments(context.Background(), address, &cursor, bindPaymentHandler(ctx, address))
+}
@@ -0,0 +1,135 @@
+package transactions
+
+import (
+	"fmt"
+
+	"github.com/nikhilsaraf/stellar-go/accounts"
+	"github.com/nikhilsaraf/stellar-go/asset"
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/federation"
+	"github.com/nikhilsaraf/stellar-go/secret"
+	"github.com/nikhilsaraf/stellar-go/stroops"
+	"github.com/nikhilsaraf/stellar-go/submit"
+	b "github.com/stellar/go/build"
+	"github.com/stellar/go/clients/horizon"
+)
+
+// PayCmd sends a payment in lumens or in an issued asset
+var PayCmd = &cli.Command{
+	Name:    "pay",
+	Summary: "send a payment in lumens or in an issued asset",
+	Usage:   "[-secret <source>] -toAddress <address|name*domain> -amount <amount> [-asset <asset>] [-memo <text>] [-dry-run | -unsigned -sequence <n>] [-out <file>]",
+	Run:     runPay,
+}
+
+func runPay(ctx *cli.Context, args []string) error {
+	fs := ctx.FlagSet()
+	secretPtr := secret.Flag(fs, "secret", "source account's secret key")
+	toAddressPtr := fs.String("toAddress", "", "destination address of the receiver's account, or a federation address such as bob*example.com")
+	amountPtr := stroops.Flag(fs, "amount", "amount to be sent with up to 7 decimal places, must be > 0")
+	memoPtr := fs.String("memo", "", "(optional) memo to include with the payment")
+	assetPtr := fs.String("asset", asset.NativeCode, "(optional) asset to pay with: native, XLM, CODE:ISSUER or CODE-ISSUER")
+	opts := submit.Flags(fs)
+	e := ctx.Parse(fs, args)
+	if e != nil {
+		return e
+	}
+	if *toAddressPtr == "" || *amountPtr <= 0 {
+		return cli.UsageErrorf("the -toAddress and -amount flags are required")
+	}
+	payAsset, e := asset.Parse(*assetPtr)
+	if e != nil {
+		return cli.UsageErrorf("invalid -asset: %s", e)
+	}
+	e = opts.Validate()
+	if e != nil {
+		return e
+	}
+	if opts.Offline() && federation.IsAddress(*toAddressPtr) {
+		return cli.UsageErrorf("federation addresses cannot be resolved without network access, use the account ID with -unsigned")
+	}
+
+	p, e := ctx.Profile()
+	if e != nil {
+		return e
+	}
+
+	sourceAddress, sourceKP, e := opts.Signer(*secretPtr, ctx)
+	if e != nil {
+		return e
+	}
+	destination, e := federation.Resolve(ctx.HTTPClient(), *toAddressPtr)
+	if e != nil {
+		return e
+	}
+	memo, e := destination.MemoMutator(*memoPtr)
+	if e != nil {
+		return e
+	}
+	destinationAddress := destination.AccountID
+	amount := *amountPtr
+
+	fmt.Fprintln(ctx.Info(), "network:", p)
+	fmt.Fprintln(ctx.Info(), "fromAddress:", sourceAddress)
+	fmt.Fprintln(ctx.Info(), "toAddress:", destination)
+	fmt.Fprintln(ctx.Info(), "amount:", amount)
+	fmt.Fprintln(ctx.Info(), "memo:", *memoPtr)
+	fmt.Fprintln(ctx.Info(), "asset:", payAsset)
+	fmt.Fprintln(ctx.Info())
+
+	horizonClient := p.Client()
+
+	// validate accounts
+	var sourceAccount, destinationAccount horizon.Account
+	if !opts.Offline() {
+		sourceAccount, e = accounts.Load(ctx, horizonClient, sourceAddress, "source")
+		if e != nil {
+			return e
+		}
+		destinationAccount, e = accounts.Load(ctx, horizonClient, destinationAddress, "destination")
+		if e != nil {
+			return e
+		}
+	}
+
+	// the issuer does not need to trust its own asset
+	if !opts.Offline() && !asset.Has(&sourceAccount, payAsset) && sourceAddress != payAsset.Issuer {
+		return fmt.Errorf("source account does not trust asset: %s", payAsset)
+	}
+	if !opts.Offline() && !asset.Has(&destinationAccount, payAsset) && destinationAddress != payAsset.Issuer {
+		return fmt.Errorf("destination account does not trust asset: %s", payAsset)
+	}
+
+	txn, e := opts.Build(p, horizonClient, sourceAddress,
+		b.Payment(
+			b.Destination{AddressOrSeed: destinationAddress},
+			payAsset.Amount(amount.String()),
+		),
+	)
+	if e != nil {
+		return e
+	}
+	if memo != nil {
+		e = txn.Mutate(memo)
+		if e != nil {
+			return e
+		}
+	}
+
+	env, e := txn.Sign()
+	if e != nil {
+		return e
+	}
+	resp, e := opts.Finish(ctx, &env, sourceKP, horizonClient)
+	if e != nil || resp == nil {
+		return e
+	}
+
+	// print final balances by reloading accounts
+	_, e = accounts.Load(ctx, horizonClient, sourceAddress, "source")
+	if e != nil {
+		return e
+	}
+	_, e = accounts.Load(ctx, horizonClient, destinationAddress, "destination")
+	return e
+}
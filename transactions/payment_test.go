@@ -0,0 +1,110 @@
+package transactions_test
+
+import (
+	"testing"
+
+	"github.com/nikhilsaraf/stellar-go/cli"
+	"github.com/nikhilsaraf/stellar-go/federation"
+	"github.com/nikhilsaraf/stellar-go/horizontest"
+	"github.com/nikhilsaraf/stellar-go/transactions"
+	"github.com/stellar/go/keypair"
+)
+
+var root = &cli.Command{Name: "stellar", Subcommands: []*cli.Command{transactions.PayCmd}}
+
+// nativeBalance returns the lumen balance of an account on the server
+func nativeBalance(t *testing.T, s *horizontest.Server, address string) string {
+	acct, ok := s.Account(address)
+	if !ok {
+		t.Fatalf("account %s does not exist", address)
+	}
+	for _, balance := range acct.Balances {
+		if balance.Asset.Type == "native" {
+			return balance.Balance
+		}
+	}
+	t.Fatalf("account %s has no lumens", address)
+	return ""
+}
+
+func TestPay(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	source, _ := keypair.Random()
+	dest, _ := keypair.Random()
+	s.CreateAccount(source.Address(), "100")
+	s.CreateAccount(dest.Address(), "100")
+
+	seq := s.Sequence(source.Address())
+	r := s.Run(root, source.Seed()+"\n", "pay", "-secret", "stdin", "-toAddress", dest.Address(), "-amount", "10", "-memo", "rent")
+	if r.Code != 0 {
+		t.Fatalf("exit %d: %s", r.Code, r.Stderr)
+	}
+	txs := s.Transactions()
+	if len(txs) != 1 {
+		t.Fatalf("got %d transactions, want 1", len(txs))
+	}
+	d := s.CheckPayment(t, txs[0].Env, source.Address(), seq, dest.Address(), "10.0000000")
+	if d.Memo.Type != "text" || d.Memo.Value != "rent" {
+		t.Errorf("memo %+v, want the text rent", d.Memo)
+	}
+	if got := nativeBalance(t, s, dest.Address()); got != "110.0000000" {
+		t.Errorf("destination balance %s, want 110.0000000", got)
+	}
+}
+
+func TestPayFederation(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	source, _ := keypair.Random()
+	dest, _ := keypair.Random()
+	s.CreateAccount(source.Address(), "100")
+	s.CreateAccount(dest.Address(), "100")
+	s.AddFederation(federation.Record{StellarAddress: "bob*example.com", AccountID: dest.Address(), MemoType: "id", Memo: "42"})
+
+	seq := s.Sequence(source.Address())
+	r := s.Run(root, source.Seed()+"\n", "pay", "-secret", "stdin", "-toAddress", "bob*example.com", "-amount", "1.5")
+	if r.Code != 0 {
+		t.Fatalf("exit %d: %s", r.Code, r.Stderr)
+	}
+	txs := s.Transactions()
+	if len(txs) != 1 {
+		t.Fatalf("got %d transactions, want 1", len(txs))
+	}
+	d := s.CheckPayment(t, txs[0].Env, source.Address(), seq, dest.Address(), "1.5000000")
+	if d.Memo.Type != "id" || d.Memo.Value != "42" {
+		t.Errorf("memo %+v, want the id 42 of the federation record", d.Memo)
+	}
+}
+
+func TestPayNotSubmitted(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	source, _ := keypair.Random()
+	dest, _ := keypair.Random()
+	issuer, _ := keypair.Random()
+	s.CreateAccount(source.Address(), "100")
+	s.CreateAccount(dest.Address(), "100")
+
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"missing amount", []string{"-toAddress", dest.Address()}, 2},
+		{"bad asset", []string{"-toAddress", dest.Address(), "-amount", "1", "-asset", "USD"}, 2},
+		{"no trust line", []string{"-toAddress", dest.Address(), "-amount", "1", "-asset", "USD:" + issuer.Address()}, 1},
+		{"missing destination", []string{"-toAddress", issuer.Address(), "-amount", "1"}, 1},
+		{"dry run", []string{"-toAddress", dest.Address(), "-amount", "1", "-dry-run"}, 0},
+	}
+	for _, test := range tests {
+		args := append([]string{"pay", "-secret", "stdin"}, test.args...)
+		r := s.Run(root, source.Seed()+"\n", args...)
+		if r.Code != test.code {
+			t.Errorf("%s: exit %d, want %d: %s", test.name, r.Code, test.code, r.Stderr)
+		}
+	}
+	if txs := s.Transactions(); len(txs) != 0 {
+		t.Errorf("got %d transactions, want none", len(txs))
+	}
+}
@@ -0,0 +1,711 @@
+// Package txerror decodes the errors Horizon returns when a transaction is submitted into result codes with a plain
+// English explanation and a suggested fix, and groups them into categories that each exit the process with their own
+// code so that scripts can react to a failure without parsing the output.
+package txerror
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/xdr"
+)
+
+// process exit codes of each category, they follow the exit codes shared by all commands in the cli package
+const (
+	// ExitFailed is used for failures that do not fit any other category
+	ExitFailed = 1
+	// ExitBadSequence means the sequence number was wrong, rebuilding the transaction usually fixes it
+	ExitBadSequence = 3
+	// ExitUnderfunded means an account did not have enough balance or reserve
+	ExitUnderfunded = 4
+	// ExitNoTrust means an account is missing a trust line or is not authorized to hold an asset
+	ExitNoTrust = 5
+	// ExitBadAuth means the transaction was not signed by the right keys
+	ExitBadAuth = 6
+	// ExitNoAccount means an account the transaction refers to does not exist, or already exists when creating it
+	ExitNoAccount = 7
+	// ExitTimeBounds means the transaction was submitted outside of its time bounds
+	ExitTimeBounds = 8
+	// ExitFee means the fee was below the network's minimum
+	ExitFee = 9
+	// ExitRejected means the transaction is invalid and will fail again unless it is changed
+	ExitRejected = 10
+	// ExitUnavailable means Horizon could not be reached or could not process the request, it is safe to retry
+	ExitUnavailable = 11
+)
+
+// Code describes a transaction or operation result code
+type Code struct {
+	// Name is the code as reported by Horizon, e.g. op_underfunded
+	Name string
+	// Explanation says what went wrong
+	Explanation string
+	// Fix suggests what to do about it
+	Fix string
+	// Exit is the process exit code for the code's category
+	Exit int
+}
+
+// Error is a failed request to Horizon
+type Error struct {
+	// Status is the HTTP status of the response, 0 when Horizon could not be reached
+	Status int
+	// Title and Detail come from Horizon's problem response
+	Title  string
+	Detail string
+	// Transaction is the transaction result code, e.g. tx_failed, empty when the failure was not a transaction result
+	Transaction Code
+	// Operations are the result codes of each operation, in the order of the operations in the transaction
+	Operations []Code
+	// ResultXDR is the base64 encoded transaction result, if Horizon returned it
+	ResultXDR string
+	// Cause is the underlying error
+	Cause error
+}
+
+// Decode converts an error returned by the Horizon client into an *Error, errors that did not come from Horizon are
+// returned unchanged
+func Decode(e error) error {
+	switch herr := errors.Cause(e).(type) {
+	case *Error:
+		return herr
+	case *horizon.Error:
+		return fromProblem(herr)
+	}
+	if isUnavailable(e) {
+		return &Error{Title: "Horizon is unreachable", Detail: e.Error(), Cause: e}
+	}
+	return e
+}
+
+func fromProblem(herr *horizon.Error) *Error {
+	result := &Error{
+		Status: herr.Problem.Status,
+		Title:  herr.Problem.Title,
+		Detail: herr.Problem.Detail,
+		Cause:  herr,
+	}
+	if result.Status == 0 && herr.Response != nil {
+		result.Status = herr.Response.StatusCode
+	}
+	if raw, ok := herr.Problem.Extras["result_xdr"]; ok {
+		// the value is a JSON string
+		result.ResultXDR = strings.Trim(string(raw), `"`)
+	}
+
+	codes, e := herr.ResultCodes()
+	if e == nil && codes != nil && codes.TransactionCode != "" {
+		result.Transaction = Lookup(codes.TransactionCode)
+		for _, op := range codes.OperationCodes {
+			result.Operations = append(result.Operations, Lookup(op))
+		}
+		return result
+	}
+	if result.ResultXDR != "" {
+		result.decodeResultXDR()
+	}
+	return result
+}
+
+// decodeResultXDR fills in the result codes from the result XDR when Horizon did not include them in the response
+func (err *Error) decodeResultXDR() {
+	var tr xdr.TransactionResult
+	e := xdr.SafeUnmarshalBase64(err.ResultXDR, &tr)
+	if e != nil {
+		return
+	}
+	name, ok := transactionCodeNames[tr.Result.Code]
+	if !ok {
+		return
+	}
+	err.Transaction = Lookup(name)
+	if tr.Result.Results == nil {
+		return
+	}
+	for _, op := range *tr.Result.Results {
+		name, ok := operationCodeNames[op.Code]
+		if op.Code == xdr.OperationResultCodeOpInner {
+			name, ok = innerCodeName(op.Tr)
+		}
+		if !ok {
+			name = "op_inner"
+		}
+		err.Operations = append(err.Operations, Lookup(name))
+	}
+}
+
+// innerCodeName returns the name of the result code of an operation that was run, the codes depend on its type
+func innerCodeName(tr *xdr.OperationResultTr) (string, bool) {
+	if tr == nil {
+		return "", false
+	}
+	var code int32
+	switch {
+	case tr.Type == xdr.OperationTypeCreateAccount && tr.CreateAccountResult != nil:
+		code = int32(tr.CreateAccountResult.Code)
+	case tr.Type == xdr.OperationTypePayment && tr.PaymentResult != nil:
+		code = int32(tr.PaymentResult.Code)
+	case tr.Type == xdr.OperationTypePathPayment && tr.PathPaymentResult != nil:
+		code = int32(tr.PathPaymentResult.Code)
+	case tr.Type == xdr.OperationTypeManageOffer && tr.ManageOfferResult != nil:
+		code = int32(tr.ManageOfferResult.Code)
+	case tr.Type == xdr.OperationTypeCreatePassiveOffer && tr.CreatePassiveOfferResult != nil:
+		code = int32(tr.CreatePassiveOfferResult.Code)
+	case tr.Type == xdr.OperationTypeSetOptions && tr.SetOptionsResult != nil:
+		code = int32(tr.SetOptionsResult.Code)
+	case tr.Type == xdr.OperationTypeChangeTrust && tr.ChangeTrustResult != nil:
+		code = int32(tr.ChangeTrustResult.Code)
+	case tr.Type == xdr.OperationTypeAllowTrust && tr.AllowTrustResult != nil:
+		code = int32(tr.AllowTrustResult.Code)
+	case tr.Type == xdr.OperationTypeAccountMerge && tr.AccountMergeResult != nil:
+		code = int32(tr.AccountMergeResult.Code)
+	case tr.Type == xdr.OperationTypeInflation && tr.InflationResult != nil:
+		code = int32(tr.InflationResult.Code)
+	case tr.Type == xdr.OperationTypeManageData && tr.ManageDataResult != nil:
+		code = int32(tr.ManageDataResult.Code)
+	case tr.Type == xdr.OperationTypeBumpSequence && tr.BumpSeqResult != nil:
+		code = int32(tr.BumpSeqResult.Code)
+	default:
+		return "", false
+	}
+	// every operation type uses 0 for success
+	if code == 0 {
+		return "op_success", true
+	}
+	// passive offers share the result codes of manage offer
+	typ := tr.Type
+	if typ == xdr.OperationTypeCreatePassiveOffer {
+		typ = xdr.OperationTypeManageOffer
+	}
+	name, ok := innerCodeNames[typ][code]
+	return name, ok
+}
+
+// isUnavailable reports whether e is a network failure rather than a response from Horizon
+func isUnavailable(e error) bool {
+	// the Horizon client wraps the errors of the HTTP client
+	e = errors.Cause(e)
+	if _, ok := e.(*url.Error); ok {
+		return true
+	}
+	_, ok := e.(net.Error)
+	return ok
+}
+
+// Error summarizes the failure on one line, e.g. "transaction failed: tx_failed (operation 0: op_underfunded)"
+func (err *Error) Error() string {
+	if err.Transaction.Name == "" {
+		if err.Status == 0 {
+			return fmt.Sprintf("%s: %s", err.Title, err.Detail)
+		}
+		return fmt.Sprintf("horizon returned %d %s: %s", err.Status, err.Title, err.Detail)
+	}
+
+	msg := "transaction failed: " + err.Transaction.Name
+	failed := []string{}
+	for i, op := range err.Operations {
+		if op.Name != "op_success" {
+			failed = append(failed, fmt.Sprintf("operation %d: %s", i, op.Name))
+		}
+	}
+	if len(failed) > 0 {
+		msg += " (" + strings.Join(failed, ", ") + ")"
+	}
+	return msg
+}
+
+// ExitCode returns the exit code of the failure's category, the most specific failed operation decides it when the
+// transaction failed because of its operations
+func (err *Error) ExitCode() int {
+	if err.Transaction.Name == "" {
+		if err.Status == 0 || err.Status == http.StatusTooManyRequests || err.Status >= 500 {
+			return ExitUnavailable
+		}
+		if err.Status == http.StatusNotFound {
+			return ExitNoAccount
+		}
+		return ExitFailed
+	}
+	if err.Transaction.Name == "tx_failed" {
+		for _, op := range err.Operations {
+			if op.Name != "op_success" {
+				return op.Exit
+			}
+		}
+	}
+	return err.Transaction.Exit
+}
+
+// Timeout reports whether the submission timed out, in which case the transaction may still be included in a ledger
+func (err *Error) Timeout() bool {
+	if err.Status == http.StatusGatewayTimeout {
+		return true
+	}
+	if ne, ok := errors.Cause(err.Cause).(net.Error); ok {
+		return ne.Timeout()
+	}
+	return false
+}
+
+// WriteText writes an explanation and a suggested fix for every code that is not a success, the summary returned by
+// Error is not repeated
+func (err *Error) WriteText(w io.Writer) {
+	if err.Transaction.Name == "" {
+		if err.ExitCode() == ExitUnavailable {
+			fmt.Fprintln(w, "the request to Horizon failed, it is safe to retry")
+			fmt.Fprintln(w, "    fix: check the Horizon URL of the network profile and try again later")
+		}
+		return
+	}
+
+	codes := []Code{}
+	if err.Transaction.Name != "tx_failed" {
+		codes = append(codes, err.Transaction)
+	}
+	for _, op := range err.Operations {
+		if op.Name != "op_success" {
+			codes = append(codes, op)
+		}
+	}
+	for _, code := range codes {
+		fmt.Fprintf(w, "%s: %s\n", code.Name, code.Explanation)
+		if code.Fix != "" {
+			fmt.Fprintf(w, "    fix: %s\n", code.Fix)
+		}
+	}
+}
+
+// Lookup returns the description of a result code, unknown codes are reported as rejected
+func Lookup(name string) Code {
+	code, ok := knownCodes[name]
+	if !ok {
+		return Code{Name: name, Explanation: "the network returned a result code this tool does not know about", Exit: ExitRejected}
+	}
+	code.Name = name
+	return code
+}
+
+var transactionCodeNames = map[xdr.TransactionResultCode]string{
+	xdr.TransactionResultCodeTxSuccess:             "tx_success",
+	xdr.TransactionResultCodeTxFailed:              "tx_failed",
+	xdr.TransactionResultCodeTxTooEarly:            "tx_too_early",
+	xdr.TransactionResultCodeTxTooLate:             "tx_too_late",
+	xdr.TransactionResultCodeTxMissingOperation:    "tx_missing_operation",
+	xdr.TransactionResultCodeTxBadSeq:              "tx_bad_seq",
+	xdr.TransactionResultCodeTxBadAuth:             "tx_bad_auth",
+	xdr.TransactionResultCodeTxInsufficientBalance: "tx_insufficient_balance",
+	xdr.TransactionResultCodeTxNoAccount:           "tx_no_source_account",
+	xdr.TransactionResultCodeTxInsufficientFee:     "tx_insufficient_fee",
+	xdr.TransactionResultCodeTxBadAuthExtra:        "tx_bad_auth_extra",
+	xdr.TransactionResultCodeTxInternalError:       "tx_internal_error",
+}
+
+// operationCodeNames covers the outer operation codes of operations that could not run, the codes of those that did
+// depend on their type and are named by innerCodeName
+var operationCodeNames = map[xdr.OperationResultCode]string{
+	xdr.OperationResultCodeOpBadAuth:   "op_bad_auth",
+	xdr.OperationResultCodeOpNoAccount: "op_no_source_account",
+}
+
+// innerCodeNames maps the failure codes of each operation type to the names Horizon reports them with
+var innerCodeNames = map[xdr.OperationType]map[int32]string{
+	xdr.OperationTypeCreateAccount: {
+		int32(xdr.CreateAccountResultCodeCreateAccountMalformed):    "op_malformed",
+		int32(xdr.CreateAccountResultCodeCreateAccountUnderfunded):  "op_underfunded",
+		int32(xdr.CreateAccountResultCodeCreateAccountLowReserve):   "op_low_reserve",
+		int32(xdr.CreateAccountResultCodeCreateAccountAlreadyExist): "op_already_exists",
+	},
+	xdr.OperationTypePayment: {
+		int32(xdr.PaymentResultCodePaymentMalformed):        "op_malformed",
+		int32(xdr.PaymentResultCodePaymentUnderfunded):      "op_underfunded",
+		int32(xdr.PaymentResultCodePaymentSrcNoTrust):       "op_src_no_trust",
+		int32(xdr.PaymentResultCodePaymentSrcNotAuthorized): "op_src_not_authorized",
+		int32(xdr.PaymentResultCodePaymentNoDestination):    "op_no_destination",
+		int32(xdr.PaymentResultCodePaymentNoTrust):          "op_no_trust",
+		int32(xdr.PaymentResultCodePaymentNotAuthorized):    "op_not_authorized",
+		int32(xdr.PaymentResultCodePaymentLineFull):         "op_line_full",
+		int32(xdr.PaymentResultCodePaymentNoIssuer):         "op_no_issuer",
+	},
+	xdr.OperationTypePathPayment: {
+		int32(xdr.PathPaymentResultCodePathPaymentMalformed):        "op_malformed",
+		int32(xdr.PathPaymentResultCodePathPaymentUnderfunded):      "op_underfunded",
+		int32(xdr.PathPaymentResultCodePathPaymentSrcNoTrust):       "op_src_no_trust",
+		int32(xdr.PathPaymentResultCodePathPaymentSrcNotAuthorized): "op_src_not_authorized",
+		int32(xdr.PathPaymentResultCodePathPaymentNoDestination):    "op_no_destination",
+		int32(xdr.PathPaymentResultCodePathPaymentNoTrust):          "op_no_trust",
+		int32(xdr.PathPaymentResultCodePathPaymentNotAuthorized):    "op_not_authorized",
+		int32(xdr.PathPaymentResultCodePathPaymentLineFull):         "op_line_full",
+		int32(xdr.PathPaymentResultCodePathPaymentNoIssuer):         "op_no_issuer",
+		int32(xdr.PathPaymentResultCodePathPaymentTooFewOffers):     "op_too_few_offers",
+		int32(xdr.PathPaymentResultCodePathPaymentOfferCrossSelf):   "op_cross_self",
+		int32(xdr.PathPaymentResultCodePathPaymentOverSendmax):      "op_over_source_max",
+	},
+	xdr.OperationTypeManageOffer: {
+		int32(xdr.ManageOfferResultCodeManageOfferMalformed):         "op_malformed",
+		int32(xdr.ManageOfferResultCodeManageOfferSellNoTrust):       "op_sell_no_trust",
+		int32(xdr.ManageOfferResultCodeManageOfferBuyNoTrust):        "op_buy_no_trust",
+		int32(xdr.ManageOfferResultCodeManageOfferSellNotAuthorized): "op_sell_not_authorized",
+		int32(xdr.ManageOfferResultCodeManageOfferBuyNotAuthorized):  "op_buy_not_authorized",
+		int32(xdr.ManageOfferResultCodeManageOfferLineFull):          "op_line_full",
+		int32(xdr.ManageOfferResultCodeManageOfferUnderfunded):       "op_underfunded",
+		int32(xdr.ManageOfferResultCodeManageOfferCrossSelf):         "op_cross_self",
+		int32(xdr.ManageOfferResultCodeManageOfferSellNoIssuer):      "op_sell_no_issuer",
+		int32(xdr.ManageOfferResultCodeManageOfferBuyNoIssuer):       "op_buy_no_issuer",
+		int32(xdr.ManageOfferResultCodeManageOfferNotFound):          "op_offer_not_found",
+		int32(xdr.ManageOfferResultCodeManageOfferLowReserve):        "op_low_reserve",
+	},
+	xdr.OperationTypeSetOptions: {
+		int32(xdr.SetOptionsResultCodeSetOptionsLowReserve):          "op_low_reserve",
+		int32(xdr.SetOptionsResultCodeSetOptionsTooManySigners):      "op_too_many_signers",
+		int32(xdr.SetOptionsResultCodeSetOptionsBadFlags):            "op_bad_flags",
+		int32(xdr.SetOptionsResultCodeSetOptionsInvalidInflation):    "op_invalid_inflation",
+		int32(xdr.SetOptionsResultCodeSetOptionsCantChange):          "op_cant_change",
+		int32(xdr.SetOptionsResultCodeSetOptionsUnknownFlag):         "op_unknown_flag",
+		int32(xdr.SetOptionsResultCodeSetOptionsThresholdOutOfRange): "op_threshold_out_of_range",
+		int32(xdr.SetOptionsResultCodeSetOptionsBadSigner):           "op_bad_signer",
+		int32(xdr.SetOptionsResultCodeSetOptionsInvalidHomeDomain):   "op_invalid_home_domain",
+	},
+	xdr.OperationTypeChangeTrust: {
+		int32(xdr.ChangeTrustResultCodeChangeTrustMalformed):      "op_malformed",
+		int32(xdr.ChangeTrustResultCodeChangeTrustNoIssuer):       "op_no_issuer",
+		int32(xdr.ChangeTrustResultCodeChangeTrustInvalidLimit):   "op_invalid_limit",
+		int32(xdr.ChangeTrustResultCodeChangeTrustLowReserve):     "op_low_reserve",
+		int32(xdr.ChangeTrustResultCodeChangeTrustSelfNotAllowed): "op_self_not_allowed",
+	},
+	xdr.OperationTypeAllowTrust: {
+		int32(xdr.AllowTrustResultCodeAllowTrustMalformed):        "op_malformed",
+		int32(xdr.AllowTrustResultCodeAllowTrustNoTrustLine):      "op_no_trust_line",
+		int32(xdr.AllowTrustResultCodeAllowTrustTrustNotRequired): "op_not_required",
+		int32(xdr.AllowTrustResultCodeAllowTrustCantRevoke):       "op_cant_revoke",
+		int32(xdr.AllowTrustResultCodeAllowTrustSelfNotAllowed):   "op_self_not_allowed",
+	},
+	xdr.OperationTypeAccountMerge: {
+		int32(xdr.AccountMergeResultCodeAccountMergeMalformed):     "op_malformed",
+		int32(xdr.AccountMergeResultCodeAccountMergeNoAccount):     "op_no_account",
+		int32(xdr.AccountMergeResultCodeAccountMergeImmutableSet):  "op_immutable_set",
+		int32(xdr.AccountMergeResultCodeAccountMergeHasSubEntries): "op_has_sub_entries",
+		int32(xdr.AccountMergeResultCodeAccountMergeSeqnumTooFar):  "op_seq_num_too_far",
+		int32(xdr.AccountMergeResultCodeAccountMergeDestFull):      "op_dest_full",
+	},
+	xdr.OperationTypeInflation: {
+		int32(xdr.InflationResultCodeInflationNotTime): "op_not_time",
+	},
+	xdr.OperationTypeManageData: {
+		int32(xdr.ManageDataResultCodeManageDataNotSupportedYet): "op_not_supported_yet",
+		int32(xdr.ManageDataResultCodeManageDataNameNotFound):    "op_data_name_not_found",
+		int32(xdr.ManageDataResultCodeManageDataLowReserve):      "op_low_reserve",
+		int32(xdr.ManageDataResultCodeManageDataInvalidName):     "op_data_invalid_name",
+	},
+	xdr.OperationTypeBumpSequence: {
+		int32(xdr.BumpSequenceResultCodeBumpSequenceBadSeq): "op_bad_seq",
+	},
+}
+
+var knownCodes = map[string]Code{
+	// transaction results
+	"tx_success": {Explanation: "the transaction succeeded", Exit: 0},
+	"tx_failed":  {Explanation: "one of the operations failed", Exit: ExitRejected},
+	"tx_too_early": {
+		Explanation: "the transaction was submitted before its minimum time",
+		Fix:         "wait until the start of its time bounds, or rebuild it without a minimum time",
+		Exit:        ExitTimeBounds,
+	},
+	"tx_too_late": {
+		Explanation: "the transaction was submitted after its maximum time and has expired",
+		Fix:         "rebuild and sign the transaction with new time bounds",
+		Exit:        ExitTimeBounds,
+	},
+	"tx_missing_operation": {
+		Explanation: "the transaction has no operations",
+		Fix:         "add at least one operation",
+		Exit:        ExitRejected,
+	},
+	"tx_bad_seq": {
+		Explanation: "the sequence number does not match the source account's next sequence number",
+		Fix:         "rebuild the transaction with the account's current sequence number + 1, another transaction from the same account may have been submitted in the meantime",
+		Exit:        ExitBadSequence,
+	},
+	"tx_bad_auth": {
+		Explanation: "the transaction is missing signatures or was signed for another network",
+		Fix:         "sign with the source account's key, check the weights of its signers and that --network matches the network it was signed for",
+		Exit:        ExitBadAuth,
+	},
+	"tx_bad_auth_extra": {
+		Explanation: "the transaction has signatures that are not needed",
+		Fix:         "remove the extra signatures",
+		Exit:        ExitBadAuth,
+	},
+	"tx_insufficient_balance": {
+		Explanation: "paying the fee would take the source account below its minimum balance",
+		Fix:         "fund the source account with more lumens",
+		Exit:        ExitUnderfunded,
+	},
+	"tx_no_source_account": {
+		Explanation: "the source account does not exist",
+		Fix:         "create and fund the source account first, e.g. with account fund on the test network",
+		Exit:        ExitNoAccount,
+	},
+	"tx_insufficient_fee": {
+		Explanation: "the fee is below the network's minimum fee",
+		Fix:         "raise the base fee of the network profile, the fee is charged per operation",
+		Exit:        ExitFee,
+	},
+	"tx_internal_error": {
+		Explanation: "the network failed to process the transaction",
+		Fix:         "try again later",
+		Exit:        ExitUnavailable,
+	},
+
+	// operation results shared by all operations
+	"op_success": {Explanation: "the operation succeeded", Exit: 0},
+	"op_inner": {
+		Explanation: "the operation failed",
+		Fix:         "decode the result XDR to see the reason",
+		Exit:        ExitRejected,
+	},
+	"op_bad_auth": {
+		Explanation: "the operation is missing signatures of its source account",
+		Fix:         "sign with the key of the operation's source account",
+		Exit:        ExitBadAuth,
+	},
+	"op_no_source_account": {
+		Explanation: "the operation's source account does not exist",
+		Fix:         "create the account before using it as a source",
+		Exit:        ExitNoAccount,
+	},
+	"op_not_supported": {
+		Explanation: "the operation is not supported by the network",
+		Exit:        ExitRejected,
+	},
+	"op_malformed": {
+		Explanation: "the operation has invalid parameters, such as a negative amount or an invalid asset",
+		Fix:         "check the amounts, assets and addresses passed to the command",
+		Exit:        ExitRejected,
+	},
+
+	// funds and reserves
+	"op_underfunded": {
+		Explanation: "the source account does not have enough of the asset to send or sell",
+		Fix:         "lower the amount or fund the account, lumens also have to cover the minimum balance",
+		Exit:        ExitUnderfunded,
+	},
+	"op_low_reserve": {
+		Explanation: "the account would fall below its minimum balance, every trust line, offer, signer and data entry raises it",
+		Fix:         "fund the account with more lumens",
+		Exit:        ExitUnderfunded,
+	},
+	"op_line_full": {
+		Explanation: "the destination's trust line limit would be exceeded",
+		Fix:         "send less or ask the receiver to raise their trust line limit",
+		Exit:        ExitUnderfunded,
+	},
+	"op_over_source_max": {
+		Explanation: "the path payment would cost more than the maximum the sender is willing to pay",
+		Fix:         "raise the maximum amount to send",
+		Exit:        ExitUnderfunded,
+	},
+	"op_too_few_offers": {
+		Explanation: "there are not enough offers on the path to convert the payment",
+		Fix:         "try a smaller amount or another path",
+		Exit:        ExitRejected,
+	},
+	"op_cross_self": {
+		Explanation: "the offer would cross another offer of the same account",
+		Fix:         "change the price or remove the existing offer",
+		Exit:        ExitRejected,
+	},
+
+	// trust lines
+	"op_no_trust": {
+		Explanation: "the destination account does not trust the asset",
+		Fix:         "the receiver has to create a trust line first, e.g. with asset trust",
+		Exit:        ExitNoTrust,
+	},
+	"op_src_no_trust": {
+		Explanation: "the source account does not trust the asset",
+		Fix:         "create a trust line to the asset first, e.g. with asset trust",
+		Exit:        ExitNoTrust,
+	},
+	"op_not_authorized": {
+		Explanation: "the destination account is not authorized by the issuer to hold the asset",
+		Fix:         "ask the issuer to authorize the receiver's trust line",
+		Exit:        ExitNoTrust,
+	},
+	"op_src_not_authorized": {
+		Explanation: "the source account is not authorized by the issuer to send the asset",
+		Fix:         "ask the issuer to authorize your trust line",
+		Exit:        ExitNoTrust,
+	},
+	"op_sell_no_trust": {
+		Explanation: "the account does not trust the asset it is selling",
+		Fix:         "create a trust line to the selling asset first",
+		Exit:        ExitNoTrust,
+	},
+	"op_buy_no_trust": {
+		Explanation: "the account does not trust the asset it is buying",
+		Fix:         "create a trust line to the buying asset first",
+		Exit:        ExitNoTrust,
+	},
+	"op_sell_not_authorized": {
+		Explanation: "the account is not authorized to sell the asset",
+		Fix:         "ask the issuer to authorize your trust line",
+		Exit:        ExitNoTrust,
+	},
+	"op_buy_not_authorized": {
+		Explanation: "the account is not authorized to buy the asset",
+		Fix:         "ask the issuer to authorize your trust line",
+		Exit:        ExitNoTrust,
+	},
+	"op_no_issuer": {
+		Explanation: "the issuer of the asset does not exist",
+		Fix:         "check the asset's issuer address",
+		Exit:        ExitNoTrust,
+	},
+	"op_sell_no_issuer": {
+		Explanation: "the issuer of the selling asset does not exist",
+		Fix:         "check the selling asset's issuer address",
+		Exit:        ExitNoTrust,
+	},
+	"op_buy_no_issuer": {
+		Explanation: "the issuer of the buying asset does not exist",
+		Fix:         "check the buying asset's issuer address",
+		Exit:        ExitNoTrust,
+	},
+	"op_invalid_limit": {
+		Explanation: "the trust line limit is below the current balance of the asset",
+		Fix:         "raise the limit, or send the balance away before lowering it",
+		Exit:        ExitNoTrust,
+	},
+	"op_no_trust_line": {
+		Explanation: "the trustor does not have a trust line to the asset",
+		Fix:         "the trustor has to create a trust line before it can be authorized",
+		Exit:        ExitNoTrust,
+	},
+	"op_self_not_allowed": {
+		Explanation: "an account cannot trust or authorize itself",
+		Exit:        ExitRejected,
+	},
+	"op_not_required": {
+		Explanation: "the issuer does not require authorization to hold its asset",
+		Fix:         "set the auth required flag on the issuer before authorizing trust lines",
+		Exit:        ExitRejected,
+	},
+	"op_cant_revoke": {
+		Explanation: "the issuer cannot revoke the authorization to hold its asset",
+		Fix:         "set the auth revocable flag on the issuer before revoking",
+		Exit:        ExitRejected,
+	},
+
+	// accounts
+	"op_no_destination": {
+		Explanation: "the destination account does not exist",
+		Fix:         "create the destination account first, a payment of lumens to a new account needs a create account operation",
+		Exit:        ExitNoAccount,
+	},
+	"op_no_account": {
+		Explanation: "the account does not exist",
+		Fix:         "check the address",
+		Exit:        ExitNoAccount,
+	},
+	"op_already_exists": {
+		Explanation: "the account being created already exists",
+		Fix:         "send a payment to it instead",
+		Exit:        ExitNoAccount,
+	},
+	"op_immutable_set": {
+		Explanation: "the account has the immutable flag set and cannot be merged",
+		Exit:        ExitRejected,
+	},
+	"op_has_sub_entries": {
+		Explanation: "the account still has trust lines, offers, signers or data entries",
+		Fix:         "remove them before merging the account",
+		Exit:        ExitRejected,
+	},
+	"op_seq_num_too_far": {
+		Explanation: "the account's sequence number is too high for it to be merged and created again",
+		Exit:        ExitRejected,
+	},
+	"op_dest_full": {
+		Explanation: "the destination cannot receive the merged lumens without exceeding the maximum balance",
+		Exit:        ExitRejected,
+	},
+	"op_bad_seq": {
+		Explanation: "the sequence number to bump to is invalid",
+		Fix:         "bump to a sequence number that is not negative",
+		Exit:        ExitRejected,
+	},
+
+	// offers
+	"op_not_found": {
+		Explanation: "the offer or entry to update does not exist",
+		Fix:         "check the offer ID with offer list",
+		Exit:        ExitRejected,
+	},
+	"op_offer_not_found": {
+		Explanation: "the offer to update or delete does not exist",
+		Fix:         "check the offer ID with offer list",
+		Exit:        ExitRejected,
+	},
+
+	// data entries
+	"op_not_supported_yet": {
+		Explanation: "data entries are not supported by the network yet",
+		Exit:        ExitRejected,
+	},
+	"op_data_name_not_found": {
+		Explanation: "the data entry to delete does not exist",
+		Fix:         "check the name of the data entry",
+		Exit:        ExitRejected,
+	},
+	"op_data_invalid_name": {
+		Explanation: "the name of the data entry is invalid",
+		Fix:         "use a name of 1 to 64 characters",
+		Exit:        ExitRejected,
+	},
+
+	// options and inflation
+	"op_invalid_inflation": {
+		Explanation: "the inflation destination does not exist",
+		Fix:         "use the address of an existing account",
+		Exit:        ExitNoAccount,
+	},
+	"op_too_many_signers": {
+		Explanation: "the account already has the maximum number of signers",
+		Exit:        ExitRejected,
+	},
+	"op_bad_flags": {
+		Explanation: "the flags to set and clear conflict",
+		Exit:        ExitRejected,
+	},
+	"op_cant_change": {
+		Explanation: "the flags cannot be changed because the account is immutable",
+		Exit:        ExitRejected,
+	},
+	"op_unknown_flag": {
+		Explanation: "an unknown flag was set",
+		Exit:        ExitRejected,
+	},
+	"op_threshold_out_of_range": {
+		Explanation: "a threshold or weight is out of range",
+		Exit:        ExitRejected,
+	},
+	"op_bad_signer": {
+		Explanation: "the signer cannot be added, an account cannot be its own extra signer",
+		Exit:        ExitRejected,
+	},
+	"op_invalid_home_domain": {
+		Explanation: "the home domain is invalid",
+		Exit:        ExitRejected,
+	},
+	"op_not_time": {
+		Explanation: "inflation cannot run yet, it runs at most once a week",
+		Fix:         "try again after the next inflation time",
+		Exit:        ExitTimeBounds,
+	},
+}
@@ -0,0 +1,113 @@
+package txerror
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/nikhilsaraf/stellar-go/horizontest"
+	"github.com/pkg/errors"
+	"github.com/stellar/go/xdr"
+)
+
+func TestInnerCodeName(t *testing.T) {
+	tests := []struct {
+		tr   *xdr.OperationResultTr
+		want string
+	}{
+		{&xdr.OperationResultTr{Type: xdr.OperationTypePayment, PaymentResult: &xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentSuccess}}, "op_success"},
+		{&xdr.OperationResultTr{Type: xdr.OperationTypePayment, PaymentResult: &xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentUnderfunded}}, "op_underfunded"},
+		{&xdr.OperationResultTr{Type: xdr.OperationTypePayment, PaymentResult: &xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentNoTrust}}, "op_no_trust"},
+		{&xdr.OperationResultTr{Type: xdr.OperationTypeCreateAccount, CreateAccountResult: &xdr.CreateAccountResult{Code: xdr.CreateAccountResultCodeCreateAccountAlreadyExist}}, "op_already_exists"},
+		{&xdr.OperationResultTr{Type: xdr.OperationTypeManageOffer, ManageOfferResult: &xdr.ManageOfferResult{Code: xdr.ManageOfferResultCodeManageOfferNotFound}}, "op_offer_not_found"},
+		{&xdr.OperationResultTr{Type: xdr.OperationTypeCreatePassiveOffer, CreatePassiveOfferResult: &xdr.ManageOfferResult{Code: xdr.ManageOfferResultCodeManageOfferSellNoTrust}}, "op_sell_no_trust"},
+		{&xdr.OperationResultTr{Type: xdr.OperationTypeAccountMerge, AccountMergeResult: &xdr.AccountMergeResult{Code: xdr.AccountMergeResultCodeAccountMergeHasSubEntries}}, "op_has_sub_entries"},
+		{&xdr.OperationResultTr{Type: xdr.OperationTypeInflation, InflationResult: &xdr.InflationResult{Code: xdr.InflationResultCodeInflationNotTime}}, "op_not_time"},
+		{&xdr.OperationResultTr{Type: xdr.OperationTypeBumpSequence, BumpSeqResult: &xdr.BumpSequenceResult{Code: xdr.BumpSequenceResultCodeBumpSequenceSuccess}}, "op_success"},
+		// the result does not match the type
+		{&xdr.OperationResultTr{Type: xdr.OperationTypeChangeTrust, PaymentResult: &xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentUnderfunded}}, ""},
+		{nil, ""},
+	}
+	for _, test := range tests {
+		got, ok := innerCodeName(test.tr)
+		if got != test.want || ok != (test.want != "") {
+			t.Errorf("%+v: got %q, %t, want %q", test.tr, got, ok, test.want)
+		}
+	}
+}
+
+func TestErrorListsFailedOperations(t *testing.T) {
+	err := &Error{
+		Transaction: Lookup("tx_failed"),
+		Operations:  []Code{Lookup("op_success"), Lookup("op_no_trust"), Lookup("op_success")},
+	}
+	if want := "transaction failed: tx_failed (operation 1: op_no_trust)"; err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+	if err.ExitCode() != ExitNoTrust {
+		t.Errorf("exit %d, want %d", err.ExitCode(), ExitNoTrust)
+	}
+}
+
+func TestDecodeUnavailable(t *testing.T) {
+	urlErr := &url.Error{Op: "Post", URL: "https://horizon-testnet.stellar.org/transactions", Err: fmt.Errorf("connection refused")}
+	tests := []struct {
+		name        string
+		in          error
+		unavailable bool
+	}{
+		{"url error", urlErr, true},
+		{"wrapped by the Horizon client", errors.Wrap(urlErr, "http post failed"), true},
+		{"other error", fmt.Errorf("something else"), false},
+		{"wrapped other error", errors.Wrap(fmt.Errorf("something else"), "context"), false},
+	}
+	for _, test := range tests {
+		decoded := Decode(test.in)
+		herr, ok := decoded.(*Error)
+		if ok != test.unavailable {
+			t.Errorf("%s: decoded to %T, unavailable should be %t", test.name, decoded, test.unavailable)
+			continue
+		}
+		if ok && herr.ExitCode() != ExitUnavailable {
+			t.Errorf("%s: exit %d, want %d", test.name, herr.ExitCode(), ExitUnavailable)
+		}
+	}
+}
+
+func TestDecodeTimeout(t *testing.T) {
+	s := horizontest.NewServer("")
+	defer s.Close()
+	s.TimeOut(1, false)
+
+	// the mock times out before looking at the envelope
+	_, e := s.Profile().Client().SubmitTransaction("AAAA")
+	if e == nil {
+		t.Fatal("expected the submission to time out")
+	}
+	herr, ok := Decode(e).(*Error)
+	if !ok {
+		t.Fatalf("decoded to %T: %s", Decode(e), e)
+	}
+	if !herr.Timeout() {
+		t.Errorf("%s is not a timeout", herr)
+	}
+	if herr.ExitCode() != ExitUnavailable {
+		t.Errorf("exit %d, want %d", herr.ExitCode(), ExitUnavailable)
+	}
+
+	// once the timeouts are used up, the same envelope is rejected as malformed
+	_, e = s.Profile().Client().SubmitTransaction("AAAA")
+	herr, ok = Decode(e).(*Error)
+	if !ok || herr.Timeout() {
+		t.Errorf("got %v, want a response that is not a timeout", e)
+	}
+
+	// a Horizon that cannot be reached is unavailable too
+	client := s.Profile().Client()
+	s.Close()
+	_, e = client.SubmitTransaction("AAAA")
+	herr, ok = Decode(e).(*Error)
+	if !ok || herr.ExitCode() != ExitUnavailable {
+		t.Errorf("got %v, want Horizon to be unavailable", e)
+	}
+}
@@ -0,0 +1,161 @@
+// Package vanity searches for key pairs whose address matches a pattern, using as many goroutines as requested.
+package vanity
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/stellar/go/keypair"
+)
+
+// alphabet is the base32 alphabet addresses are encoded with
+const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+
+// addressLength is the length of an encoded address
+const addressLength = 56
+
+// ErrCancelled is returned by Search when it is stopped before finding a match
+var ErrCancelled = fmt.Errorf("vanity search cancelled")
+
+// Matcher checks addresses against a prefix, a suffix and a regular expression, any of which may be empty
+type Matcher struct {
+	prefix string
+	suffix string
+	regex  *regexp.Regexp
+}
+
+// Compile validates the pattern and returns a Matcher for it, patterns that no address can match are rejected.
+// The prefix may be given with or without the leading G that every address starts with.
+func Compile(prefix string, suffix string, expr string) (*Matcher, error) {
+	prefix = strings.ToUpper(prefix)
+	suffix = strings.ToUpper(suffix)
+	if prefix == "" && suffix == "" && expr == "" {
+		return nil, fmt.Errorf("at least one of a prefix, a suffix or a regular expression is required")
+	}
+
+	if prefix != "" && prefix[0] != 'G' {
+		prefix = "G" + prefix
+	}
+	e := checkAlphabet("prefix", prefix)
+	if e != nil {
+		return nil, e
+	}
+	e = checkAlphabet("suffix", suffix)
+	if e != nil {
+		return nil, e
+	}
+	// the second character holds the low bits of the version byte, which are zero, and the top 2 bits of the key
+	if len(prefix) > 1 && !strings.ContainsRune("ABCD", rune(prefix[1])) {
+		return nil, fmt.Errorf("no address can start with %s, the character after the G must be one of A, B, C or D", prefix[:2])
+	}
+	if len(prefix) > addressLength || len(suffix) > addressLength-1 {
+		return nil, fmt.Errorf("the pattern is longer than an address")
+	}
+
+	m := &Matcher{prefix: prefix, suffix: suffix}
+	if expr != "" {
+		m.regex, e = regexp.Compile(expr)
+		if e != nil {
+			return nil, fmt.Errorf("invalid regular expression: %s", e)
+		}
+	}
+	return m, nil
+}
+
+func checkAlphabet(name string, s string) error {
+	for _, c := range s {
+		if !strings.ContainsRune(alphabet, c) {
+			return fmt.Errorf("the %s can only contain the characters A-Z and 2-7, '%c' is not allowed", name, c)
+		}
+	}
+	return nil
+}
+
+// Match reports whether the address matches the pattern
+func (m *Matcher) Match(address string) bool {
+	return strings.HasPrefix(address, m.prefix) &&
+		strings.HasSuffix(address, m.suffix) &&
+		(m.regex == nil || m.regex.MatchString(address))
+}
+
+// ExpectedAttempts estimates the number of key pairs that need to be generated to find a match, it is 0 when the
+// difficulty cannot be estimated because the pattern uses a regular expression
+func (m *Matcher) ExpectedAttempts() float64 {
+	if m.regex != nil {
+		return 0
+	}
+	attempts := math.Pow(32, float64(len(m.suffix)))
+	if len(m.prefix) > 1 {
+		// the first character is always G and the second one is one of 4
+		attempts *= 4 * math.Pow(32, float64(len(m.prefix)-2))
+	}
+	return attempts
+}
+
+// Search generates random key pairs on the given number of workers until one matches, attempts is updated as the
+// search progresses so the caller can report on it. Closing done stops the search and returns ErrCancelled.
+func Search(m *Matcher, workers int, done <-chan struct{}, attempts *uint64) (*keypair.Full, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	found := make(chan *keypair.Full, 1)
+	errs := make(chan error, workers)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e := search(m, stop, found, attempts)
+			if e != nil {
+				errs <- e
+			}
+		}()
+	}
+
+	var result *keypair.Full
+	var e error
+	select {
+	case result = <-found:
+	case e = <-errs:
+	case <-done:
+		e = ErrCancelled
+	}
+	close(stop)
+	wg.Wait()
+	return result, e
+}
+
+// search is run by each worker, it returns nil when stopped
+func search(m *Matcher, stop <-chan struct{}, found chan<- *keypair.Full, attempts *uint64) error {
+	var rawSeed [32]byte
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		_, e := rand.Read(rawSeed[:])
+		if e != nil {
+			return e
+		}
+		kp, e := keypair.FromRawSeed(rawSeed)
+		if e != nil {
+			return e
+		}
+		atomic.AddUint64(attempts, 1)
+		if m.Match(kp.Address()) {
+			select {
+			case found <- kp:
+			default:
+			}
+			return nil
+		}
+	}
+}